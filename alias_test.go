@@ -0,0 +1,79 @@
+package flags
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOptionAliases(t *testing.T) {
+	var opts = struct {
+		Color string `long:"color" long:"colour"`
+		Quiet []bool `short:"q" short:"s" long:"quiet" long-alias:"silent"`
+	}{}
+
+	assertParseSuccess(t, &opts, "--colour", "red", "-q", "-s", "--silent", "--quiet")
+
+	assertString(t, opts.Color, "red")
+
+	if len(opts.Quiet) != 4 {
+		t.Errorf("Expected quiet to be set 4 times, but got %v", opts.Quiet)
+	}
+
+	p := NewParser(&opts, None)
+
+	if p.FindOptionByLongName("silent") != p.FindOptionByShortName('s') {
+		t.Errorf("Expected aliases to find the same option")
+	}
+}
+
+func TestOptionAliasesDuplicate(t *testing.T) {
+	var opts = struct {
+		Color  string `long:"color" long:"colour"`
+		Colour string `long:"colour"`
+	}{}
+
+	p := NewNamedParser("test", None)
+	_, err := p.AddGroup("Application Options", "", &opts)
+
+	assertError(t, err, ErrDuplicatedFlag, "option `"+defaultLongOptDelimiter+"colour' uses the same long name as option `"+defaultLongOptDelimiter+"color'")
+}
+
+func TestOptionAliasesHelp(t *testing.T) {
+	var opts = struct {
+		Color string `long:"color" long:"colour" description:"The color"`
+		Quiet bool   `short:"q" short:"s" long:"quiet" description:"Be quiet"`
+	}{}
+
+	p := NewNamedParser("test", None)
+	p.OptionStyle = OptionStylePOSIX
+	p.AddGroup("Application Options", "", &opts)
+
+	var help bytes.Buffer
+	p.WriteHelp(&help)
+
+	expected := `Usage:
+  test
+
+Application Options:
+      --color, --colour= The color
+  -q, -s, --quiet        Be quiet
+`
+
+	assertDiff(t, help.String(), expected, "help message")
+
+	var man bytes.Buffer
+	p.WriteManPage(&man)
+
+	if !strings.Contains(man.String(), "\\fB\\-q\\fR, \\fB\\-s\\fR, \\fB\\-\\-quiet\\fR") {
+		t.Errorf("Expected man page to contain all names, but got:\n%s", man.String())
+	}
+
+	c := &completion{parser: p}
+	ret := c.complete([]string{"--col"})
+
+	if !reflect.DeepEqual(ret, []Completion{{Item: "--color", Description: "The color"}, {Item: "--colour", Description: "The color"}}) {
+		t.Errorf("Unexpected completions %v", ret)
+	}
+}
@@ -25,3 +25,7 @@ type Arg struct {
 func (a *Arg) isRemaining() bool {
 	return a.value.Type().Kind() == reflect.Slice
 }
+
+func (a *Arg) isArray() bool {
+	return a.value.Type().Kind() == reflect.Array
+}
@@ -0,0 +1,118 @@
+package flags
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestArrayOption(t *testing.T) {
+	var opts = struct {
+		Color  [3]uint8   `long:"color"`
+		Bounds [4]float64 `long:"bounds" delim:","`
+	}{}
+
+	assertParseSuccess(t, &opts, "--color", "255", "--color", "128", "--color", "0", "--bounds", "0,0.5,1,1.5")
+
+	if opts.Color != [3]uint8{255, 128, 0} {
+		t.Errorf("Unexpected color %v", opts.Color)
+	}
+
+	if opts.Bounds != [4]float64{0, 0.5, 1, 1.5} {
+		t.Errorf("Unexpected bounds %v", opts.Bounds)
+	}
+}
+
+func TestArrayOptionCount(t *testing.T) {
+	var opts = struct {
+		Color  [3]uint8   `long:"color"`
+		Bounds [4]float64 `long:"bounds" delim:","`
+	}{}
+
+	assertParseFail(t, ErrExpectedArgument, "expected 3 values for flag `"+defaultLongOptDelimiter+"color', but got 2",
+		&opts, "--color", "1", "--color", "2")
+
+	assertParseFail(t, ErrMarshal, "too many values for flag `"+defaultLongOptDelimiter+"bounds', expected 4",
+		&opts, "--bounds", "1,2,3,4,5")
+}
+
+func TestArrayOptionDefault(t *testing.T) {
+	var opts = struct {
+		Color [3]uint8 `long:"color" default:"1" default:"2" default:"3"`
+		Size  [2]int   `long:"size" default:"640,480" delim:","`
+	}{}
+
+	assertParseSuccess(t, &opts, "--size", "800,600")
+
+	if opts.Color != [3]uint8{1, 2, 3} {
+		t.Errorf("Unexpected color %v", opts.Color)
+	}
+
+	if opts.Size != [2]int{800, 600} {
+		t.Errorf("Unexpected size %v", opts.Size)
+	}
+
+	var invalid = struct {
+		Color [3]uint8 `long:"color" default:"1"`
+	}{}
+
+	assertParseFail(t, ErrExpectedArgument, "expected 3 values for flag `"+defaultLongOptDelimiter+"color', but got 1", &invalid)
+}
+
+func TestArrayOptionIni(t *testing.T) {
+	var opts = struct {
+		Color [3]uint8 `long:"color"`
+		Size  [2]int   `long:"size" delim:","`
+	}{}
+
+	p := NewNamedParser("test", None)
+	p.AddGroup("Application Options", "", &opts)
+
+	opts.Color = [3]uint8{1, 2, 3}
+	opts.Size = [2]int{800, 600}
+
+	var b bytes.Buffer
+	inip := NewIniParser(p)
+	inip.Write(&b, IniNone)
+
+	assertString(t, b.String(), "[Application Options]\nColor = 1\nColor = 2\nColor = 3\nSize = 800,600\n\n")
+
+	opts.Color = [3]uint8{}
+	opts.Size = [2]int{}
+
+	if err := inip.Parse(strings.NewReader(b.String())); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if opts.Color != [3]uint8{1, 2, 3} || opts.Size != [2]int{800, 600} {
+		t.Errorf("Unexpected values %v and %v", opts.Color, opts.Size)
+	}
+
+	err := inip.Parse(strings.NewReader("[Application Options]\nColor = 1\n"))
+
+	if inierr, ok := err.(*IniError); !ok {
+		t.Errorf("Expected IniError, but got %v", err)
+	} else {
+		assertString(t, inierr.Message, "expected 3 values for flag `"+defaultLongOptDelimiter+"color', but got 1")
+	}
+}
+
+func TestArrayPositional(t *testing.T) {
+	var opts = struct {
+		Positional struct {
+			Point [2]int
+			Rest  []string
+		} `positional-args:"yes"`
+	}{}
+
+	ret := assertParseSuccess(t, &opts, "1", "2", "a", "b")
+
+	if opts.Positional.Point != [2]int{1, 2} {
+		t.Errorf("Unexpected point %v", opts.Positional.Point)
+	}
+
+	assertStringArray(t, opts.Positional.Rest, []string{"a", "b"})
+	assertStringArray(t, ret, []string{})
+
+	assertParseFail(t, ErrRequired, "the required argument `Point (exactly 2 arguments, but got 1)` was not provided", &opts, "1")
+}
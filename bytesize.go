@@ -0,0 +1,134 @@
+package flags
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is an option value type representing a number of bytes. It can be
+// specified on the command line using either SI (decimal) or IEC (binary)
+// unit suffixes, e.g. 512MiB, 1.5GB or 100k. Suffixes are matched case
+// insensitively: k, kb (1000) and ki, kib (1024) up to the exa (e) prefix.
+// A number without suffix, or with the b suffix, is a number of bytes.
+type ByteSize uint64
+
+// Byte size units.
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000
+	MB          = 1000 * KB
+	GB          = 1000 * MB
+	TB          = 1000 * GB
+	PB          = 1000 * TB
+	EB          = 1000 * PB
+
+	KiB ByteSize = 1 << 10
+	MiB ByteSize = 1 << 20
+	GiB ByteSize = 1 << 30
+	TiB ByteSize = 1 << 40
+	PiB ByteSize = 1 << 50
+	EiB ByteSize = 1 << 60
+)
+
+type byteSizeUnit struct {
+	suffix string
+	size   ByteSize
+}
+
+// byteSizeUnits lists the units from largest to smallest, with the IEC unit
+// before the SI unit of the same prefix.
+var byteSizeUnits = []byteSizeUnit{
+	{"EiB", EiB}, {"EB", EB},
+	{"PiB", PiB}, {"PB", PB},
+	{"TiB", TiB}, {"TB", TB},
+	{"GiB", GiB}, {"GB", GB},
+	{"MiB", MiB}, {"MB", MB},
+	{"KiB", KiB}, {"KB", KB},
+}
+
+func byteSizeMultiplier(suffix string) (ByteSize, bool) {
+	s := strings.ToLower(suffix)
+
+	switch s {
+	case "", "b":
+		return Byte, true
+	}
+
+	s = strings.TrimSuffix(s, "b")
+
+	for _, unit := range byteSizeUnits {
+		name := strings.ToLower(strings.TrimSuffix(unit.suffix, "B"))
+
+		if s == name {
+			return unit.size, true
+		}
+	}
+
+	return 0, false
+}
+
+// UnmarshalFlag parses a byte size with an optional unit suffix.
+func (b *ByteSize) UnmarshalFlag(value string) error {
+	s := strings.TrimSpace(value)
+
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+
+	if i < 0 {
+		i = len(s)
+	}
+
+	number, suffix := s[:i], strings.TrimSpace(s[i:])
+
+	multiplier, ok := byteSizeMultiplier(suffix)
+
+	if number == "" || !ok {
+		return newMessageError(MsgInvalidByteSize, value)
+	}
+
+	if n, err := strconv.ParseUint(number, 10, 64); err == nil {
+		if n > math.MaxUint64/uint64(multiplier) {
+			return newMessageError(MsgByteSizeOutOfRange, value)
+		}
+
+		*b = ByteSize(n) * multiplier
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+
+	if err != nil {
+		return newMessageError(MsgInvalidByteSize, value)
+	}
+
+	f *= float64(multiplier)
+
+	if f >= math.MaxUint64 {
+		return newMessageError(MsgByteSizeOutOfRange, value)
+	}
+
+	*b = ByteSize(f)
+	return nil
+}
+
+// MarshalFlag formats the byte size using the unit resulting in the smallest
+// exact number, preferring IEC units.
+func (b ByteSize) MarshalFlag() (string, error) {
+	return b.String(), nil
+}
+
+// String formats the byte size (see MarshalFlag).
+func (b ByteSize) String() string {
+	best := byteSizeUnit{"", Byte}
+
+	for _, unit := range byteSizeUnits {
+		if b != 0 && b%unit.size == 0 && unit.size > best.size {
+			best = unit
+		}
+	}
+
+	return strconv.FormatUint(uint64(b/best.size), 10) + best.suffix
+}
@@ -0,0 +1,101 @@
+package flags
+
+import (
+	"testing"
+)
+
+func TestByteSizeUnmarshal(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected ByteSize
+	}{
+		{"0", 0},
+		{"123", 123},
+		{"123b", 123},
+		{"1k", KB},
+		{"1KB", KB},
+		{"1KiB", KiB},
+		{"1ki", KiB},
+		{"512MiB", 512 * MiB},
+		{"512mib", 512 * MiB},
+		{"1.5GB", 1500 * MB},
+		{"2 TB", 2 * TB},
+		{"16EiB", 0},
+	}
+
+	for _, test := range tests {
+		var b ByteSize
+
+		err := b.UnmarshalFlag(test.value)
+
+		if test.value == "16EiB" {
+			if err == nil {
+				t.Errorf("Expected out of range error for %s", test.value)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %v", test.value, err)
+			continue
+		}
+
+		if b != test.expected {
+			t.Errorf("Expected %s to be %d, but got %d", test.value, test.expected, b)
+		}
+	}
+
+	for _, value := range []string{"", "MiB", "12XB", "1.2.3k"} {
+		var b ByteSize
+
+		if err := b.UnmarshalFlag(value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
+
+func TestByteSizeMarshal(t *testing.T) {
+	tests := []struct {
+		value    ByteSize
+		expected string
+	}{
+		{0, "0"},
+		{123, "123"},
+		{KB, "1KB"},
+		{KiB, "1KiB"},
+		{512 * MiB, "512MiB"},
+		{1500 * MB, "1500MB"},
+		{1024000, "1000KiB"},
+	}
+
+	for _, test := range tests {
+		s, err := test.value.MarshalFlag()
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		assertString(t, s, test.expected)
+	}
+}
+
+func TestByteSizeOption(t *testing.T) {
+	var opts = struct {
+		Cache ByteSize `long:"cache" default:"64MiB"`
+	}{}
+
+	assertParseSuccess(t, &opts)
+
+	if opts.Cache != 64*MiB {
+		t.Errorf("Expected Cache to be 64MiB, but got %v", opts.Cache)
+	}
+
+	assertParseSuccess(t, &opts, "--cache=1GB")
+
+	if opts.Cache != GB {
+		t.Errorf("Expected Cache to be 1GB, but got %v", opts.Cache)
+	}
+
+	assertParseFail(t, ErrMarshal, "invalid argument for flag `"+defaultLongOptDelimiter+"cache' (expected flags.ByteSize): invalid byte size `lots'", &opts, "--cache=lots")
+}
@@ -58,7 +58,19 @@ type lookup struct {
 	shortNames map[string]*Option
 	longNames  map[string]*Option
 
+	// Options declared with a prefix, keyed by the prefix and name
+	prefixedShortNames map[string]*Option
+	prefixedLongNames  map[string]*Option
+
 	commands map[string]*Command
+
+	// The parser whose options determine how long names are looked up
+	parser *Parser
+}
+
+// longOption returns the option with the given long name.
+func (l *lookup) longOption(name string) *Option {
+	return l.longNames[l.parser.longNameKey(name)]
 }
 
 // AddCommand adds a new command to the parser with the given name and data. The
@@ -321,7 +333,13 @@ func (c *Command) makeLookup() lookup {
 	ret := lookup{
 		shortNames: make(map[string]*Option),
 		longNames:  make(map[string]*Option),
-		commands:   make(map[string]*Command),
+
+		prefixedShortNames: make(map[string]*Option),
+		prefixedLongNames:  make(map[string]*Option),
+
+		commands: make(map[string]*Command),
+
+		parser: c.parser(),
 	}
 
 	parent := c.parent
@@ -348,12 +366,20 @@ func (c *Command) makeLookup() lookup {
 func (c *Command) fillLookup(ret *lookup, onlyOptions bool) {
 	c.eachGroup(func(g *Group) {
 		for _, option := range g.options {
-			if option.ShortName != 0 {
-				ret.shortNames[string(option.ShortName)] = option
+			shortNames, longNames := ret.shortNames, ret.longNames
+			prefix := ""
+
+			if option.Prefix != 0 {
+				shortNames, longNames = ret.prefixedShortNames, ret.prefixedLongNames
+				prefix = string(option.Prefix)
+			}
+
+			for _, name := range option.shortNames() {
+				shortNames[prefix+string(name)] = option
 			}
 
-			if len(option.LongName) > 0 {
-				ret.longNames[option.LongNameWithNamespace()] = option
+			for _, name := range option.longNamesWithNamespace() {
+				longNames[prefix+ret.parser.longNameKey(name)] = option
 			}
 		}
 	})
@@ -459,6 +485,7 @@ func (c *Command) hasHelpOptions() bool {
 func (c *Command) fillParseState(s *parseState) {
 	s.positional = make([]*Arg, len(c.args))
 	copy(s.positional, c.args)
+	s.positionalIndex = 0
 
 	s.lookup = c.makeLookup()
 	s.command = c
@@ -2,6 +2,7 @@ package flags
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -80,7 +81,7 @@ func (c *completion) skipPositional(s *parseState, n int) {
 }
 
 func (c *completion) completeOptionNames(s *parseState, prefix string, match string, short bool) []Completion {
-	if short && len(match) != 0 {
+	if short && len(match) != 0 && (c.parser.Options&SingleDashLong) == None {
 		return []Completion{
 			{
 				Item: prefix + match,
@@ -91,15 +92,21 @@ func (c *completion) completeOptionNames(s *parseState, prefix string, match str
 	var results []Completion
 	repeats := map[string]bool{}
 
+	// Long names are matched by their lookup key, but completed using the
+	// name of the option
+	key := c.parser.longNameKey(match)
+
 	for name, opt := range s.lookup.longNames {
-		if strings.HasPrefix(name, match) && !opt.Hidden {
+		if strings.HasPrefix(name, key) && !opt.Hidden {
 			results = append(results, Completion{
-				Item:        defaultLongOptDelimiter + name,
-				Description: opt.Description,
+				Item:        c.parser.longOptDelimiter() + c.longNameByKey(opt, name),
+				Description: opt.description(),
 			})
 
 			if short {
-				repeats[string(opt.ShortName)] = true
+				for _, r := range opt.shortNames() {
+					repeats[string(r)] = true
+				}
 			}
 		}
 	}
@@ -108,8 +115,8 @@ func (c *completion) completeOptionNames(s *parseState, prefix string, match str
 		for name, opt := range s.lookup.shortNames {
 			if _, exist := repeats[name]; !exist && strings.HasPrefix(name, match) && !opt.Hidden {
 				results = append(results, Completion{
-					Item:        string(defaultShortOptDelimiter) + name,
-					Description: opt.Description,
+					Item:        string(c.parser.shortOptDelimiter()) + name,
+					Description: opt.description(),
 				})
 			}
 		}
@@ -118,6 +125,55 @@ func (c *completion) completeOptionNames(s *parseState, prefix string, match str
 	return results
 }
 
+// longNameByKey returns the long name or alias of opt which is looked up
+// by the given key.
+func (c *completion) longNameByKey(opt *Option, key string) string {
+	for _, name := range opt.longNamesWithNamespace() {
+		if c.parser.longNameKey(name) == key {
+			return name
+		}
+	}
+
+	return key
+}
+
+func (c *completion) completePrefixedNames(s *parseState, prefix OptionPrefix, match string) []Completion {
+	var results []Completion
+
+	pchar := string(prefix.Char)
+	added := map[*Option]bool{}
+
+	complete := func(names map[string]*Option, trim string, islong bool) {
+		for name := range names {
+			if !strings.HasPrefix(name, trim) {
+				continue
+			}
+
+			name = name[len(trim):]
+
+			if !strings.HasPrefix(name, match) {
+				continue
+			}
+
+			if opt, _ := s.lookupPrefixed(prefix, name, islong); opt != nil && !opt.Hidden && !added[opt] {
+				results = append(results, Completion{
+					Item:        pchar + name,
+					Description: opt.description(),
+				})
+
+				added[opt] = true
+			}
+		}
+	}
+
+	complete(s.lookup.prefixedLongNames, pchar, true)
+	complete(s.lookup.longNames, "", true)
+	complete(s.lookup.prefixedShortNames, pchar, false)
+	complete(s.lookup.shortNames, "", false)
+
+	return results
+}
+
 func (c *completion) completeNamesForLongPrefix(s *parseState, prefix string, match string) []Completion {
 	return c.completeOptionNames(s, prefix, match, false)
 }
@@ -142,7 +198,9 @@ func (c *completion) completeCommands(s *parseState, match string) []Completion
 }
 
 func (c *completion) completeValue(value reflect.Value, prefix string, match string) []Completion {
-	if value.Kind() == reflect.Slice {
+	value = unwrapOptional(value)
+
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
 		value = reflect.New(value.Type().Elem())
 	}
 	i := value.Interface()
@@ -157,6 +215,12 @@ func (c *completion) completeValue(value reflect.Value, prefix string, match str
 		}
 	}
 
+	if ret == nil {
+		if enum := enumOf(reflect.Indirect(value).Type()); enum != nil {
+			ret = enumCompletions(enum, match)
+		}
+	}
+
 	for i, v := range ret {
 		ret[i].Item = prefix + v.Item
 	}
@@ -177,6 +241,9 @@ func (c *completion) complete(args []string) []Completion {
 
 	var opt *Option
 
+	// The index of the argument of opt which is being completed
+	var optArgument int
+
 	for len(s.args) > 1 {
 		arg := s.pop()
 
@@ -187,16 +254,23 @@ func (c *completion) complete(args []string) []Completion {
 			break
 		}
 
-		if argumentIsOption(arg) {
-			prefix, optname, islong := stripOptionPrefix(arg)
-			optname, _, argument := splitOption(prefix, optname, islong)
+		if c.parser.argumentIsOption(arg) {
+			prefix, optname, islong := c.parser.stripOptionPrefix(arg)
+
+			if !islong && c.parser.isSingleDashLong(s, prefix, optname) {
+				islong = true
+			}
+
+			optname, _, argument := c.parser.splitOption(prefix, optname, islong)
 
 			if argument == nil {
 				var o *Option
 				canarg := true
 
-				if islong {
-					o = s.lookup.longNames[optname]
+				if optionPrefix, ok := c.parser.optionPrefix(prefix); ok {
+					o, _ = s.lookupPrefixed(optionPrefix, optname, islong)
+				} else if islong {
+					o = s.lookup.longOption(optname)
 				} else {
 					for i, r := range optname {
 						sname := string(r)
@@ -219,20 +293,29 @@ func (c *completion) complete(args []string) []Completion {
 
 					break
 				} else if o != nil && o.canArgument() && !o.OptionalArgument && canarg {
-					if len(s.args) > 1 {
+					// Skip the arguments of the option, unless the last
+					// argument is one of them
+					nargs, maximum := o.nargs()
+					n := 0
+
+					for n < maximum && (n < nargs || !c.parser.argumentStartsOption(s.peek())) {
+						if len(s.args) == 1 {
+							opt = o
+							optArgument = n
+
+							break
+						}
+
 						s.pop()
-					} else {
-						opt = o
+						n++
 					}
 				}
 			}
 		} else {
 			if len(s.positional) > 0 {
-				if !s.positional[0].isRemaining() {
-					// Don't advance beyond a remaining positional arg (because
-					// it consumes all subsequent args).
-					s.positional = s.positional[1:]
-				}
+				// Don't advance beyond a remaining positional arg (because
+				// it consumes all subsequent args).
+				s.advancePositional()
 			} else if cmd, ok := s.lookup.commands[arg]; ok {
 				cmd.fillParseState(s)
 			}
@@ -246,13 +329,26 @@ func (c *completion) complete(args []string) []Completion {
 
 	if opt != nil {
 		// Completion for the argument of 'opt'
-		ret = c.completeValue(opt.value, "", lastarg)
-	} else if argumentStartsOption(lastarg) {
+		ret = c.completeValue(opt.argumentValue(optArgument), "", lastarg)
+	} else if c.parser.argumentStartsOption(lastarg) {
 		// Complete the option
-		prefix, optname, islong := stripOptionPrefix(lastarg)
-		optname, split, argument := splitOption(prefix, optname, islong)
+		prefix, optname, islong := c.parser.stripOptionPrefix(lastarg)
+
+		if !islong && c.parser.isSingleDashLong(s, prefix, optname) {
+			islong = true
+		}
 
-		if argument == nil && !islong {
+		optname, split, argument := c.parser.splitOption(prefix, optname, islong)
+
+		if optionPrefix, ok := c.parser.optionPrefix(prefix); ok {
+			if argument == nil {
+				ret = c.completePrefixedNames(s, optionPrefix, optname)
+			} else if opt, _ := s.lookupPrefixed(optionPrefix, optname, islong); opt != nil {
+				ret = c.completeValue(opt.value, prefix+optname+split, *argument)
+			}
+		} else if argument == nil && !islong && (c.parser.Options&SingleDashLong) != None {
+			ret = c.completeNamesForShortPrefix(s, prefix, optname)
+		} else if argument == nil && !islong {
 			rname, n := utf8.DecodeRuneInString(optname)
 			sname := string(rname)
 
@@ -263,7 +359,7 @@ func (c *completion) complete(args []string) []Completion {
 			}
 		} else if argument != nil {
 			if islong {
-				opt = s.lookup.longNames[optname]
+				opt = s.lookup.longOption(optname)
 			} else {
 				opt = s.lookup.shortNames[optname]
 			}
@@ -288,7 +384,7 @@ func (c *completion) complete(args []string) []Completion {
 	return ret
 }
 
-func (c *completion) print(items []Completion, showDescriptions bool) {
+func (c *completion) print(w io.Writer, items []Completion, showDescriptions bool) {
 	if showDescriptions && len(items) > 1 {
 		maxl := 0
 
@@ -299,17 +395,17 @@ func (c *completion) print(items []Completion, showDescriptions bool) {
 		}
 
 		for _, v := range items {
-			fmt.Printf("%s", v.Item)
+			fmt.Fprintf(w, "%s", v.Item)
 
 			if len(v.Description) > 0 {
-				fmt.Printf("%s  # %s", strings.Repeat(" ", maxl-len(v.Item)), v.Description)
+				fmt.Fprintf(w, "%s  # %s", strings.Repeat(" ", maxl-len(v.Item)), v.Description)
 			}
 
-			fmt.Printf("\n")
+			fmt.Fprintf(w, "\n")
 		}
 	} else {
 		for _, v := range items {
-			fmt.Println(v.Item)
+			fmt.Fprintln(w, v.Item)
 		}
 	}
 }
@@ -2,7 +2,6 @@ package flags
 
 import (
 	"bytes"
-	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -296,39 +295,23 @@ func TestParserCompletion(t *testing.T) {
 			os.Setenv("GO_FLAGS_COMPLETION", "1")
 		}
 
-		tmp := os.Stdout
-
-		r, w, _ := os.Pipe()
-		os.Stdout = w
-
-		out := make(chan string)
-
-		go func() {
-			var buf bytes.Buffer
-
-			io.Copy(&buf, r)
-
-			out <- buf.String()
-		}()
+		var buf bytes.Buffer
 
 		p := NewParser(&completionTestOptions, None)
+		p.Stdout = &buf
 
 		p.CompletionHandler = func(items []Completion) {
 			comp := &completion{parser: p}
-			comp.print(items, test.ShowDescriptions)
+			comp.print(p.Stdout, items, test.ShowDescriptions)
 		}
 
 		_, err := p.ParseArgs(test.Args)
 
-		w.Close()
-
-		os.Stdout = tmp
-
 		if err != nil {
 			t.Fatalf("Unexpected error: %s", err)
 		}
 
-		got := strings.Split(strings.Trim(<-out, "\n"), "\n")
+		got := strings.Split(strings.Trim(buf.String(), "\n"), "\n")
 
 		if !reflect.DeepEqual(got, test.Completed) {
 			t.Errorf("Expected: %#v\nGot: %#v", test.Completed, got)
@@ -337,3 +320,40 @@ func TestParserCompletion(t *testing.T) {
 
 	os.Setenv("GO_FLAGS_COMPLETION", "")
 }
+
+func TestCompletionSingleDashLong(t *testing.T) {
+	var opts struct {
+		Verbose bool         `short:"v" long:"verbose"`
+		Version bool         `long:"version"`
+		Port    int          `short:"p" long:"port"`
+		Name    TestComplete `long:"name"`
+	}
+
+	p := NewParser(&opts, SingleDashLong)
+	p.OptionStyle = OptionStylePOSIX
+
+	c := &completion{parser: p}
+
+	tests := []struct {
+		args      []string
+		completed []string
+	}{
+		{[]string{"-ver"}, []string{"-verbose", "-version"}},
+		{[]string{"-v"}, []string{"-verbose", "-version"}},
+		{[]string{"-name", "hello u"}, []string{"hello universe"}},
+		{[]string{"-name=hello w"}, []string{"-name=hello world"}},
+	}
+
+	for _, test := range tests {
+		ret := c.complete(test.args)
+		items := make([]string, len(ret))
+
+		for i, v := range ret {
+			items[i] = v.Item
+		}
+
+		if !reflect.DeepEqual(items, test.completed) {
+			t.Errorf("Args: %#v\n  Expected: %#v\n  Got:     %#v", test.args, test.completed, items)
+		}
+	}
+}
@@ -5,11 +5,13 @@
 package flags
 
 import (
-	"fmt"
+	"encoding"
+	"flag"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Marshaler is the interface implemented by types that can marshal themselves
@@ -51,19 +53,101 @@ func getBase(options multiTag, base int) (int, error) {
 	return base, err
 }
 
-func convertMarshal(val reflect.Value) (bool, string, error) {
-	// Check first for the Marshaler interface
-	if val.IsValid() && val.Type().NumMethod() > 0 && val.CanInterface() {
-		if marshaler, ok := val.Interface().(Marshaler); ok {
-			ret, err := marshaler.MarshalFlag()
-			return true, ret, err
+// marshalFunc returns a function marshaling i to a string if i implements
+// Marshaler, encoding.TextMarshaler or flag.Value (in that order of
+// preference), or nil otherwise.
+func marshalFunc(i interface{}) func() (string, error) {
+	switch m := i.(type) {
+	case Marshaler:
+		return m.MarshalFlag
+	case encoding.TextMarshaler:
+		return func() (string, error) {
+			b, err := m.MarshalText()
+			return string(b), err
+		}
+	case flag.Value:
+		return func() (string, error) {
+			return m.String(), nil
+		}
+	}
+
+	return nil
+}
+
+// unmarshalFunc returns a function unmarshaling a string into i if i
+// implements Unmarshaler, encoding.TextUnmarshaler or flag.Value (in that
+// order of preference), or nil otherwise.
+func unmarshalFunc(i interface{}) func(string) error {
+	switch u := i.(type) {
+	case Unmarshaler:
+		return u.UnmarshalFlag
+	case encoding.TextUnmarshaler:
+		return func(value string) error {
+			return u.UnmarshalText([]byte(value))
+		}
+	case flag.Value:
+		return u.Set
+	}
+
+	return nil
+}
+
+// valueMarshalFunc returns the marshal function (see marshalFunc) of val, or
+// of its address if val is addressable.
+func valueMarshalFunc(val reflect.Value) func() (string, error) {
+	if !val.IsValid() {
+		return nil
+	}
+
+	// Methods cannot be called on nil pointers with value receivers
+	if val.Kind() == reflect.Ptr && val.IsNil() {
+		return nil
+	}
+
+	if val.Type().NumMethod() > 0 && val.CanInterface() {
+		if marshal := marshalFunc(val.Interface()); marshal != nil {
+			return marshal
 		}
 	}
 
+	if val.Kind() != reflect.Ptr && val.CanAddr() && val.Addr().CanInterface() {
+		return marshalFunc(val.Addr().Interface())
+	}
+
+	return nil
+}
+
+// isMarshaler returns whether val marshals itself as a whole (see
+// convertMarshal), as opposed to being converted based on its kind.
+func isMarshaler(val reflect.Value) bool {
+	return valueMarshalFunc(val) != nil
+}
+
+func convertMarshal(val reflect.Value) (bool, string, error) {
+	// Check first for the marshaling interfaces
+	if marshal := valueMarshalFunc(val); marshal != nil {
+		ret, err := marshal()
+		return true, ret, err
+	}
+
 	return false, "", nil
 }
 
 func convertToString(val reflect.Value, options multiTag) (string, error) {
+	if o, ok := asOptional(val); ok {
+		if o.optionalSource() == SourceNone {
+			return "", nil
+		}
+
+		return convertToString(o.optionalElem(), options)
+	}
+
+	if val.IsValid() {
+		if ok, ret := convertSpecialToString(val, options); ok {
+			return ret, nil
+		}
+	}
+
 	if ok, ret, err := convertMarshal(val); ok {
 		return ret, err
 	}
@@ -76,8 +160,7 @@ func convertToString(val reflect.Value, options multiTag) (string, error) {
 
 	// Support for time.Duration
 	if tp == reflect.TypeOf((*time.Duration)(nil)).Elem() {
-		stringer := val.Interface().(fmt.Stringer)
-		return stringer.String(), nil
+		return formatDuration(time.Duration(val.Int())), nil
 	}
 
 	switch tp.Kind() {
@@ -107,7 +190,7 @@ func convertToString(val reflect.Value, options multiTag) (string, error) {
 		return strconv.FormatUint(val.Uint(), base), nil
 	case reflect.Float32, reflect.Float64:
 		return strconv.FormatFloat(val.Float(), 'g', -1, tp.Bits()), nil
-	case reflect.Slice:
+	case reflect.Slice, reflect.Array:
 		if val.Len() == 0 {
 			return "", nil
 		}
@@ -153,6 +236,10 @@ func convertToString(val reflect.Value, options multiTag) (string, error) {
 		}
 
 		return ret + "}", nil
+	case reflect.Struct:
+		if structOptionType(tp) != nil {
+			return convertStructToString(val)
+		}
 	case reflect.Ptr:
 		return convertToString(reflect.Indirect(val), options)
 	case reflect.Interface:
@@ -166,15 +253,15 @@ func convertToString(val reflect.Value, options multiTag) (string, error) {
 
 func convertUnmarshal(val string, retval reflect.Value) (bool, error) {
 	if retval.Type().NumMethod() > 0 && retval.CanInterface() {
-		if unmarshaler, ok := retval.Interface().(Unmarshaler); ok {
-			if retval.IsNil() {
+		if unmarshal := unmarshalFunc(retval.Interface()); unmarshal != nil {
+			if retval.Kind() == reflect.Ptr && retval.IsNil() {
 				retval.Set(reflect.New(retval.Type().Elem()))
 
 				// Re-assign from the new value
-				unmarshaler = retval.Interface().(Unmarshaler)
+				unmarshal = unmarshalFunc(retval.Interface())
 			}
 
-			return true, unmarshaler.UnmarshalFlag(val)
+			return true, unmarshal(val)
 		}
 	}
 
@@ -189,7 +276,26 @@ func convertUnmarshal(val string, retval reflect.Value) (bool, error) {
 	return false, nil
 }
 
-func convert(val string, retval reflect.Value, options multiTag) error {
+func convert(p *Parser, val string, retval reflect.Value, options multiTag) error {
+	// Values converted into an Optional are set from the command line,
+	// unless the option records a different source afterwards
+	if o, ok := asOptional(retval); ok {
+		if err := convert(p, val, o.optionalElem(), options); err != nil {
+			return err
+		}
+
+		o.setOptionalSource(SourceCommandLine)
+		return nil
+	}
+
+	if err := checkEnum(val, retval); err != nil {
+		return err
+	}
+
+	if ok, err := convertSpecial(val, retval, options); ok {
+		return err
+	}
+
 	if ok, err := convertUnmarshal(val, retval); ok {
 		return err
 	}
@@ -198,7 +304,7 @@ func convert(val string, retval reflect.Value, options multiTag) error {
 
 	// Support for time.Duration
 	if tp == reflect.TypeOf((*time.Duration)(nil)).Elem() {
-		parsed, err := time.ParseDuration(val)
+		parsed, err := parseDuration(val)
 
 		if err != nil {
 			return err
@@ -265,7 +371,7 @@ func convert(val string, retval reflect.Value, options multiTag) error {
 		elemvalptr := reflect.New(elemtp)
 		elemval := reflect.Indirect(elemvalptr)
 
-		if err := convert(val, elemval, options); err != nil {
+		if err := convert(p, val, elemval, options); err != nil {
 			return err
 		}
 
@@ -283,14 +389,14 @@ func convert(val string, retval reflect.Value, options multiTag) error {
 		keytp := tp.Key()
 		keyval := reflect.New(keytp)
 
-		if err := convert(key, keyval, options); err != nil {
+		if err := convert(p, key, keyval, options); err != nil {
 			return err
 		}
 
 		valuetp := tp.Elem()
 		valueval := reflect.New(valuetp)
 
-		if err := convert(value, valueval, options); err != nil {
+		if err := convert(p, value, valueval, options); err != nil {
 			return err
 		}
 
@@ -299,15 +405,19 @@ func convert(val string, retval reflect.Value, options multiTag) error {
 		}
 
 		retval.SetMapIndex(reflect.Indirect(keyval), reflect.Indirect(valueval))
+	case reflect.Struct:
+		if structOptionType(tp) != nil {
+			return convertStruct(p, val, retval)
+		}
 	case reflect.Ptr:
 		if retval.IsNil() {
 			retval.Set(reflect.New(retval.Type().Elem()))
 		}
 
-		return convert(val, reflect.Indirect(retval), options)
+		return convert(p, val, reflect.Indirect(retval), options)
 	case reflect.Interface:
 		if !retval.IsNil() {
-			return convert(val, retval.Elem(), options)
+			return convert(p, val, retval.Elem(), options)
 		}
 	}
 
@@ -352,6 +462,90 @@ func quoteV(s []string) []string {
 	return ret
 }
 
+// splitDelimited splits s at each occurrence of delim. Parts enclosed in
+// double quotes are unquoted and may contain the delimiter, a backslash
+// outside of quotes escapes the following character.
+func splitDelimited(s string, delim string) ([]string, error) {
+	var parts []string
+	var part strings.Builder
+
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			_, n := utf8.DecodeRuneInString(s[i+1:])
+
+			part.WriteString(s[i+1 : i+1+n])
+			i += 1 + n
+		case s[i] == '"':
+			j := i + 1
+
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+
+				j++
+			}
+
+			if j >= len(s) {
+				return nil, newMessageError(MsgUnterminatedQuote, s)
+			}
+
+			unquoted, err := strconv.Unquote(s[i : j+1])
+
+			if err != nil {
+				return nil, err
+			}
+
+			part.WriteString(unquoted)
+			i = j + 1
+		case strings.HasPrefix(s[i:], delim):
+			parts = append(parts, part.String())
+			part.Reset()
+
+			i += len(delim)
+		default:
+			part.WriteByte(s[i])
+			i++
+		}
+	}
+
+	return append(parts, part.String()), nil
+}
+
+// joinDelimited joins parts using delim, escaping backslashes, double quotes
+// and delimiters in the parts such that splitDelimited returns the original
+// parts.
+func joinDelimited(parts []string, delim string) string {
+	escaped := make([]string, len(parts))
+
+	for i, part := range parts {
+		var b strings.Builder
+
+		for j := 0; j < len(part); {
+			if part[j] == '\\' || part[j] == '"' {
+				b.WriteByte('\\')
+				b.WriteByte(part[j])
+				j++
+			} else if strings.HasPrefix(part[j:], delim) {
+				for _, c := range delim {
+					b.WriteRune('\\')
+					b.WriteRune(c)
+				}
+
+				j += len(delim)
+			} else {
+				b.WriteByte(part[j])
+				j++
+			}
+		}
+
+		escaped[i] = b.String()
+	}
+
+	return strings.Join(escaped, delim)
+}
+
 func unquoteIfPossible(s string) (string, error) {
 	if len(s) == 0 || s[0] != '"' {
 		return s, nil
@@ -0,0 +1,40 @@
+package flags
+
+// DefaultProvider is the interface implemented by group and command data
+// which compute default values at runtime, e.g. the number of CPUs or the
+// home directory of the user, which cannot be given in a default tag.
+// OptionDefault is consulted for every option of the group when the defaults
+// are applied, and the defaults it provides are shown in the help and man
+// page and written to ini files in the same way as those of the default tag.
+// Environment variables still take precedence over provided defaults.
+type DefaultProvider interface {
+	// OptionDefault returns the default values of the given option. If it
+	// returns nil values, the values of the default tag of the option are
+	// used instead.
+	OptionDefault(option *Option) ([]string, error)
+}
+
+// defaults returns the default values of the option, as provided by the
+// DefaultProvider of its group or otherwise given by its default tag.
+func (option *Option) defaults() ([]string, error) {
+	if option.group != nil {
+		if provider, ok := option.group.data.(DefaultProvider); ok {
+			values, err := provider.OptionDefault(option)
+
+			if err != nil {
+				p := option.parser()
+
+				ret := p.newError(ErrUnknown, MsgDefaultProvider, option, err)
+				ret.Err = err
+
+				return nil, ret
+			}
+
+			if values != nil {
+				return values, nil
+			}
+		}
+	}
+
+	return option.Default, nil
+}
@@ -0,0 +1,138 @@
+package flags
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+type defaultProviderOptions struct {
+	Workers int    `long:"workers" env:"TEST_WORKERS" description:"Number of workers"`
+	Home    string `long:"home" default:"/home/default" description:"Home directory"`
+	Name    string `long:"name" default:"static" description:"Host name"`
+
+	hostErr error
+}
+
+func (o *defaultProviderOptions) OptionDefault(option *Option) ([]string, error) {
+	switch option.Field().Name {
+	case "Workers":
+		return []string{"8"}, nil
+	case "Home":
+		return []string{"/home/provided"}, nil
+	case "Name":
+		if o.hostErr != nil {
+			return nil, o.hostErr
+		}
+	}
+
+	return nil, nil
+}
+
+func TestDefaultProvider(t *testing.T) {
+	var opts defaultProviderOptions
+
+	assertParseSuccess(t, &opts)
+
+	if opts.Workers != 8 {
+		t.Errorf("Expected 8 workers, but got %d", opts.Workers)
+	}
+
+	assertString(t, opts.Home, "/home/provided")
+
+	// The default tag is used when the provider returns no values
+	assertString(t, opts.Name, "static")
+
+	opts = defaultProviderOptions{}
+	assertParseSuccess(t, &opts, "--workers", "2")
+
+	if opts.Workers != 2 {
+		t.Errorf("Expected 2 workers, but got %d", opts.Workers)
+	}
+}
+
+func TestDefaultProviderEnv(t *testing.T) {
+	oldEnv := EnvSnapshot()
+	defer oldEnv.Restore()
+
+	os.Setenv("TEST_WORKERS", "4")
+
+	var opts defaultProviderOptions
+	assertParseSuccess(t, &opts)
+
+	if opts.Workers != 4 {
+		t.Errorf("Expected 4 workers, but got %d", opts.Workers)
+	}
+
+	p := NewParser(&opts, None)
+	p.ParseArgs(nil)
+
+	if source := p.FindOptionByLongName("workers").Source(); source != SourceEnv {
+		t.Errorf("Expected source %v, but got %v", SourceEnv, source)
+	}
+}
+
+func TestDefaultProviderError(t *testing.T) {
+	hostErr := errors.New("no hostname")
+	opts := defaultProviderOptions{hostErr: hostErr}
+
+	p := NewParser(&opts, None)
+	_, err := p.ParseArgs(nil)
+
+	assertError(t, err, ErrUnknown, fmt.Sprintf("could not determine the default value of flag `%sname': no hostname", defaultLongOptDelimiter))
+
+	if !errors.Is(err, hostErr) {
+		t.Errorf("Expected error to wrap %v, but got %v", hostErr, err)
+	}
+
+	// The provider is not consulted for options given on the command line
+	opts = defaultProviderOptions{hostErr: hostErr}
+	assertParseSuccess(t, &opts, "--name", "given")
+}
+
+func TestDefaultProviderHelp(t *testing.T) {
+	var opts defaultProviderOptions
+
+	p := NewNamedParser("TestDefaultProvider", None)
+	p.AddGroup("Application Options", "", &opts)
+	p.OptionStyle = OptionStylePOSIX
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	p.WriteHelp(&out)
+
+	for _, s := range []string{"(default: 8)", "(default: /home/provided)", "(default: static)"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("Expected help to contain %q, but got:\n%s", s, out.String())
+		}
+	}
+}
+
+func TestDefaultProviderIni(t *testing.T) {
+	var opts defaultProviderOptions
+
+	p := NewNamedParser("TestDefaultProvider", None)
+	p.AddGroup("Application Options", "", &opts)
+
+	if _, err := p.ParseArgs([]string{defaultLongOptDelimiter + "home" + string(defaultNameArgDelimiter) + "/home/given"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	NewIniParser(p).Write(&out, IniIncludeDefaults|IniCommentDefaults)
+
+	expected := `[Application Options]
+; Workers = 8
+Home = /home/given
+; Name = static
+
+`
+
+	assertDiff(t, out.String(), expected, "ini")
+}
@@ -0,0 +1,114 @@
+package flags
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDelim(t *testing.T) {
+	var opts = struct {
+		Tags   []string       `long:"tags" delim:","`
+		Ints   []int          `long:"ints" delim:";" default:"1;2"`
+		Values map[string]int `long:"values" delim:","`
+		Plain  []string       `long:"plain"`
+	}{}
+
+	assertParseSuccess(t, &opts,
+		"--tags=a,b", "--tags", `c,"d,e",f\,g`,
+		"--values=x:1,y:2",
+		"--plain=h,i")
+
+	assertStringArray(t, opts.Tags, []string{"a", "b", "c", "d,e", "f,g"})
+	assertStringArray(t, opts.Plain, []string{"h,i"})
+
+	if len(opts.Ints) != 2 || opts.Ints[0] != 1 || opts.Ints[1] != 2 {
+		t.Errorf("Expected Ints to be [1 2], but got %v", opts.Ints)
+	}
+
+	if len(opts.Values) != 2 || opts.Values["x"] != 1 || opts.Values["y"] != 2 {
+		t.Errorf("Expected Values to be map[x:1 y:2], but got %v", opts.Values)
+	}
+
+	assertParseSuccess(t, &opts, "--ints=3;4;5")
+
+	if len(opts.Ints) != 3 || opts.Ints[2] != 5 {
+		t.Errorf("Expected Ints to be [3 4 5], but got %v", opts.Ints)
+	}
+
+	assertParseFail(t, ErrMarshal, "invalid argument for flag `"+defaultLongOptDelimiter+"tags' (expected []string): unterminated quote in `a,\"b'", &opts, `--tags=a,"b`)
+}
+
+func TestDelimChoices(t *testing.T) {
+	var opts = struct {
+		Colors []string `long:"colors" delim:"," choice:"red" choice:"green"`
+	}{}
+
+	assertParseSuccess(t, &opts, "--colors=red,green")
+	assertStringArray(t, opts.Colors, []string{"red", "green"})
+
+	assertParseFail(t, ErrInvalidChoice, "Invalid value `blue' for option `"+defaultLongOptDelimiter+"colors'. Allowed values are: red or green", &opts, "--colors=red,blue")
+}
+
+func TestDelimEnv(t *testing.T) {
+	oldEnv := EnvSnapshot()
+	defer oldEnv.Restore()
+
+	var opts = struct {
+		Tags []string `long:"tags" delim:"," env:"TEST_TAGS"`
+	}{}
+
+	os.Setenv("TEST_TAGS", "a,b")
+
+	assertParseSuccess(t, &opts)
+	assertStringArray(t, opts.Tags, []string{"a", "b"})
+}
+
+func TestDelimInvalidTag(t *testing.T) {
+	var opts = struct {
+		Tag string `long:"tag" delim:","`
+	}{}
+
+	assertParseFail(t, ErrInvalidTag, "delim tag can only be used with slice, array or map options, not `tag'", &opts)
+}
+
+func TestDelimHelpIni(t *testing.T) {
+	var opts = struct {
+		Tags   []string       `long:"tags" delim:"," default:"a,b" description:"tags"`
+		Values map[string]int `long:"values" delim:"," description:"values"`
+	}{}
+
+	p := NewNamedParser("test", None)
+	p.AddGroup("Application Options", "", &opts)
+
+	if _, err := p.ParseArgs([]string{"--values=y:2,x:1", "--tags", `c,d\,e`}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var help bytes.Buffer
+	p.WriteHelp(&help)
+
+	if !strings.Contains(help.String(), "tags (default: a,b)") {
+		t.Errorf("Expected help to contain the joined default, but got:\n%s", help.String())
+	}
+
+	var ini bytes.Buffer
+	inip := NewIniParser(p)
+	inip.Write(&ini, IniNone)
+
+	assertString(t, ini.String(), "[Application Options]\nTags = c,d\\,e\nValues = x:1,y:2\n\n")
+
+	opts.Tags = nil
+	opts.Values = nil
+
+	if err := inip.Parse(&ini); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertStringArray(t, opts.Tags, []string{"c", "d,e"})
+
+	if len(opts.Values) != 2 || opts.Values["x"] != 1 || opts.Values["y"] != 2 {
+		t.Errorf("Expected Values to be map[x:1 y:2], but got %v", opts.Values)
+	}
+}
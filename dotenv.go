@@ -0,0 +1,286 @@
+package flags
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// DotenvError contains location information on where an error occurred while
+// loading a dotenv file.
+type DotenvError struct {
+	// The error message.
+	Message string
+
+	// The filename of the file in which the error occurred.
+	File string
+
+	// The line number at which the error occurred.
+	LineNumber uint
+}
+
+// Error provides a "file:line: message" formatted message of the dotenv
+// error.
+func (x *DotenvError) Error() string {
+	return fmt.Sprintf(
+		"%s:%d: %s",
+		x.File,
+		x.LineNumber,
+		x.Message,
+	)
+}
+
+// LoadDotenvFile loads environment variables from a dotenv file. See
+// LoadDotenv for more information. The returned errors can be of the type
+// flags.DotenvError, or errors opening the file.
+func (p *Parser) LoadDotenvFile(filename string) error {
+	file, err := os.Open(filename)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	return p.loadDotenv(file, filename)
+}
+
+// LoadDotenv loads environment variables from the dotenv format. The loaded
+// variables are used for the env keys of options (and for variables expanded
+// with ExpandVariables) in the same way as the environment, without modifying
+// the environment of the process. Variables set in the environment of the
+// process take precedence over loaded variables, and variables loaded later
+// take precedence over variables loaded earlier.
+//
+// The format of the dotenv file is as follows:
+//
+//	# A comment
+//	NAME=value # Another comment
+//	export OTHER_NAME="double quoted value\nwith escapes"
+//	RAW='single quoted value without escapes'
+//
+// Double and single quoted values may span multiple lines. In double quoted
+// values, the escapes \n, \r, \t, \", \\ and \$ are supported.
+//
+// The returned errors can be of the type flags.DotenvError.
+func (p *Parser) LoadDotenv(reader io.Reader) error {
+	return p.loadDotenv(reader, "")
+}
+
+func (p *Parser) loadDotenv(reader io.Reader, filename string) error {
+	values, err := readDotenv(p, reader, filename)
+
+	if err != nil {
+		return err
+	}
+
+	if p.dotenv == nil {
+		p.dotenv = make(map[string]string)
+	}
+
+	for name, value := range values {
+		p.dotenv[name] = value
+	}
+
+	return nil
+}
+
+// lookupEnv looks up the environment variable with the given name in the
+// environment of the process, and otherwise in the loaded dotenv variables.
+func (p *Parser) lookupEnv(name string) (string, bool) {
+	if value, ok := os.LookupEnv(name); ok {
+		return value, true
+	}
+
+	if p != nil {
+		if value, ok := p.dotenv[name]; ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// envNames returns the names of the variables in the environment of the
+// process and in the loaded dotenv variables.
+func (p *Parser) envNames() []string {
+	var ret []string
+	seen := make(map[string]bool)
+
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+
+		ret = append(ret, name)
+		seen[name] = true
+	}
+
+	for name := range p.dotenv {
+		if !seen[name] {
+			ret = append(ret, name)
+		}
+	}
+
+	return ret
+}
+
+func readDotenv(p *Parser, contents io.Reader, filename string) (map[string]string, error) {
+	data, err := io.ReadAll(contents)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	ret := make(map[string]string)
+
+	for i := 0; i < len(lines); i++ {
+		lineno := uint(i + 1)
+		line := strings.TrimSpace(lines[i])
+
+		// Skip empty lines and comments
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		if rest := strings.TrimPrefix(line, "export"); rest != line && len(rest) != 0 && (rest[0] == ' ' || rest[0] == '\t') {
+			line = strings.TrimSpace(rest)
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+
+		if !ok {
+			return nil, &DotenvError{
+				Message:    p.Message(MsgMalformedDotenv, line),
+				File:       filename,
+				LineNumber: lineno,
+			}
+		}
+
+		name = strings.TrimSpace(name)
+
+		if !isDotenvName(name) {
+			return nil, &DotenvError{
+				Message:    p.Message(MsgInvalidDotenvName, name),
+				File:       filename,
+				LineNumber: lineno,
+			}
+		}
+
+		value = strings.TrimSpace(value)
+
+		if len(value) != 0 && (value[0] == '"' || value[0] == '\'') {
+			quote := value[0]
+			rest := value[1:]
+			end := dotenvClosingQuote(rest, quote)
+
+			// Quoted values may span multiple lines
+			for end < 0 && i+1 < len(lines) {
+				i++
+				rest += "\n" + lines[i]
+				end = dotenvClosingQuote(rest, quote)
+			}
+
+			if end < 0 {
+				return nil, &DotenvError{
+					Message:    p.Message(MsgUnterminatedDotenvValue, name),
+					File:       filename,
+					LineNumber: lineno,
+				}
+			}
+
+			if trailing := strings.TrimSpace(rest[end+1:]); len(trailing) != 0 && trailing[0] != '#' {
+				return nil, &DotenvError{
+					Message:    p.Message(MsgDotenvTrailingCharacters, name),
+					File:       filename,
+					LineNumber: uint(i + 1),
+				}
+			}
+
+			value = rest[:end]
+
+			if quote == '"' {
+				value = unescapeDotenv(value)
+			}
+		} else {
+			// Comments after unquoted values need to be preceded by
+			// white space
+			for idx := 0; idx < len(value); idx++ {
+				if value[idx] == '#' && (idx == 0 || value[idx-1] == ' ' || value[idx-1] == '\t') {
+					value = strings.TrimSpace(value[:idx])
+					break
+				}
+			}
+		}
+
+		ret[name] = value
+	}
+
+	return ret, nil
+}
+
+// isDotenvName returns whether name is a valid dotenv variable name, i.e. it
+// consists of letters, digits, underscores and dots and does not start with
+// a digit.
+func isDotenvName(name string) bool {
+	if len(name) == 0 || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') && r != '_' && r != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dotenvClosingQuote returns the index of the closing quote in s, or -1 if
+// there is none. In double quoted values, quotes can be escaped.
+func dotenvClosingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		if quote == '"' && s[i] == '\\' {
+			i++
+		} else if s[i] == quote {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// unescapeDotenv replaces the escapes in a double quoted dotenv value.
+// Unknown escapes are kept as is.
+func unescapeDotenv(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+
+	var ret strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			ret.WriteByte(s[i])
+			continue
+		}
+
+		i++
+
+		switch s[i] {
+		case 'n':
+			ret.WriteByte('\n')
+		case 'r':
+			ret.WriteByte('\r')
+		case 't':
+			ret.WriteByte('\t')
+		case '"', '\\', '$':
+			ret.WriteByte(s[i])
+		default:
+			ret.WriteByte('\\')
+			ret.WriteByte(s[i])
+		}
+	}
+
+	return ret.String()
+}
@@ -0,0 +1,163 @@
+package flags
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadDotenv(t *testing.T) {
+	contents := `# A comment
+APP_PLAIN=plain value
+  export APP_EXPORTED=exported
+APP_COMMENT=value # comment
+APP_HASH=value#not-a-comment
+APP_EMPTY=
+APP_DOUBLE="double \"quoted\"\tvalue\n\$HOME" # comment
+APP_SINGLE='single \n quoted'
+APP_MULTI="first
+second"
+exporter=not exported
+`
+
+	values, err := readDotenv(nil, strings.NewReader(contents), "test.env")
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"APP_PLAIN":    "plain value",
+		"APP_EXPORTED": "exported",
+		"APP_COMMENT":  "value",
+		"APP_HASH":     "value#not-a-comment",
+		"APP_EMPTY":    "",
+		"APP_DOUBLE":   "double \"quoted\"\tvalue\n$HOME",
+		"APP_SINGLE":   "single \\n quoted",
+		"APP_MULTI":    "first\nsecond",
+		"exporter":     "not exported",
+	}
+
+	if len(values) != len(expected) {
+		t.Errorf("Expected %d values, but got %d: %v", len(expected), len(values), values)
+	}
+
+	for name, value := range expected {
+		assertString(t, values[name], value)
+	}
+}
+
+func TestReadDotenvErrors(t *testing.T) {
+	tests := []struct {
+		contents string
+		msg      string
+	}{
+		{
+			"A=1\n\nMALFORMED\n",
+			"test.env:3: malformed NAME=value (MALFORMED)",
+		},
+		{
+			"A=1\n1A=2\n",
+			"test.env:2: invalid variable name `1A'",
+		},
+		{
+			"A=1\nB=\"unterminated\nvalue\n",
+			"test.env:2: unterminated quoted value for `B'",
+		},
+		{
+			"A='quoted' trailing\n",
+			"test.env:1: unexpected characters after quoted value for `A'",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := readDotenv(nil, strings.NewReader(test.contents), "test.env")
+
+		if _, ok := err.(*DotenvError); !ok {
+			t.Fatalf("Expected *DotenvError, but got %v", err)
+		}
+
+		assertString(t, err.Error(), test.msg)
+	}
+}
+
+func TestLoadDotenv(t *testing.T) {
+	oldEnv := EnvSnapshot()
+	defer oldEnv.Restore()
+
+	os.Setenv("TEST_DOTENV_PROCESS", "process")
+	os.Unsetenv("TEST_DOTENV_VALUE")
+
+	var opts struct {
+		Value   string `long:"value" env:"TEST_DOTENV_VALUE"`
+		Process string `long:"process" env:"TEST_DOTENV_PROCESS"`
+		Later   string `long:"later" env:"TEST_DOTENV_LATER"`
+	}
+
+	p := NewParser(&opts, None)
+
+	if err := p.LoadDotenv(strings.NewReader("TEST_DOTENV_VALUE=dotenv\nTEST_DOTENV_PROCESS=dotenv\nTEST_DOTENV_LATER=first\n")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := p.LoadDotenv(strings.NewReader("TEST_DOTENV_LATER=second\n")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.Value, "dotenv")
+	assertString(t, opts.Later, "second")
+
+	// The environment of the process takes precedence
+	assertString(t, opts.Process, "process")
+
+	if source := p.FindOptionByLongName("value").Source(); source != SourceEnv {
+		t.Errorf("Expected source %v, but got %v", SourceEnv, source)
+	}
+
+	// The environment of the process is not modified
+	if _, ok := os.LookupEnv("TEST_DOTENV_VALUE"); ok {
+		t.Errorf("Expected TEST_DOTENV_VALUE not to be set in the environment")
+	}
+}
+
+func TestLoadDotenvFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), ".env")
+
+	if err := os.WriteFile(filename, []byte("APP_LOG_LEVEL=debug\nAPP_LOG_LEVL=typo\n"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var opts struct {
+		LogLevel string `long:"log-level"`
+	}
+
+	p := NewParser(&opts, ErrorOnUnknownEnv)
+	p.EnvPrefix = "APP"
+
+	if err := p.LoadDotenvFile(filename); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Loaded variables are checked for typos as well
+	_, err := p.ParseArgs(nil)
+	assertError(t, err, ErrUnknownEnv, "unknown environment variable `APP_LOG_LEVL', did you mean `APP_LOG_LEVEL'?")
+
+	p.Options = None
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.LogLevel, "debug")
+
+	err = p.LoadDotenvFile(filepath.Join(t.TempDir(), "missing.env"))
+
+	if !os.IsNotExist(err) {
+		t.Errorf("Expected a not exist error, but got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package flags
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// parseDuration parses a duration like time.ParseDuration, additionally
+// supporting the units "d" (days) and "w" (weeks), e.g. 1w2d12h.
+func parseDuration(s string) (time.Duration, error) {
+	if !strings.ContainsAny(s, "dw") {
+		return time.ParseDuration(s)
+	}
+
+	orig := s
+	neg := false
+
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	// The total is accumulated unsigned, like time.ParseDuration does, so
+	// that the most negative duration can be represented
+	var total uint64
+	limit := uint64(math.MaxInt64)
+
+	if neg {
+		limit++
+	}
+
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+
+		if i <= 0 {
+			return 0, fmt.Errorf("time: invalid duration %s", strconv.Quote(orig))
+		}
+
+		j := strings.IndexFunc(s[i:], func(r rune) bool {
+			return (r >= '0' && r <= '9') || r == '.'
+		})
+
+		if j < 0 {
+			j = len(s)
+		} else {
+			j += i
+		}
+
+		number, unit := s[:i], s[i:j]
+		s = s[j:]
+
+		var part time.Duration
+
+		switch unit {
+		case "d", "w":
+			size := day
+
+			if unit == "w" {
+				size = week
+			}
+
+			f, err := strconv.ParseFloat(number, 64)
+
+			// Durations which do not fit in an int64 would silently
+			// overflow when converted
+			if err != nil || f >= math.MaxInt64/float64(size) {
+				return 0, fmt.Errorf("time: invalid duration %s", strconv.Quote(orig))
+			}
+
+			part = time.Duration(f * float64(size))
+		default:
+			var err error
+
+			if part, err = time.ParseDuration(number + unit); err != nil {
+				return 0, fmt.Errorf("time: invalid duration %s", strconv.Quote(orig))
+			}
+		}
+
+		if uint64(part) > limit-total {
+			return 0, fmt.Errorf("time: invalid duration %s", strconv.Quote(orig))
+		}
+
+		total += uint64(part)
+	}
+
+	if neg {
+		return -time.Duration(total), nil
+	}
+
+	return time.Duration(total), nil
+}
+
+// formatDuration formats a duration like time.Duration.String, using days
+// for durations of at least one day, e.g. 30d or 1d12h0m0s.
+func formatDuration(d time.Duration) string {
+	if d < day && d > -day {
+		return d.String()
+	}
+
+	sign := ""
+	days := d / day
+	rest := d % day
+
+	// Negate the parts rather than d, which cannot be negated if it is
+	// math.MinInt64
+	if d < 0 {
+		sign = "-"
+		days = -days
+		rest = -rest
+	}
+
+	ret := sign + strconv.FormatInt(int64(days), 10) + "d"
+
+	if rest != 0 {
+		ret += rest.String()
+	}
+
+	return ret
+}
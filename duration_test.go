@@ -0,0 +1,96 @@
+package flags
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"1h30m", 90 * time.Minute},
+		{"30d", 30 * day},
+		{"2w", 2 * week},
+		{"1w2d12h", week + 2*day + 12*time.Hour},
+		{"1.5d", 36 * time.Hour},
+		{"-1d", -day},
+		{"106751d", 106751 * day},
+		{"-106751d", -106751 * day},
+	}
+
+	for _, test := range tests {
+		d, err := parseDuration(test.value)
+
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %v", test.value, err)
+			continue
+		}
+
+		if d != test.expected {
+			t.Errorf("Expected %s to be %v, but got %v", test.value, test.expected, d)
+		}
+	}
+
+	for _, value := range []string{"d", "1x", "1d2", "1dd"} {
+		if _, err := parseDuration(value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+
+	// Values which do not fit in a time.Duration
+	for _, value := range []string{"107000d", "200000d", "99999999999w", "106751d24h", "15250w2d", "-106751d23h47m16.854775809s"} {
+		if _, err := parseDuration(value); err == nil || err.Error() != "time: invalid duration \""+value+"\"" {
+			t.Errorf("Expected invalid duration error for %q, but got %v", value, err)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		value    time.Duration
+		expected string
+	}{
+		{90 * time.Minute, "1h30m0s"},
+		{30 * day, "30d"},
+		{day + 12*time.Hour, "1d12h0m0s"},
+		{-2 * day, "-2d"},
+		{math.MaxInt64, "106751d23h47m16.854775807s"},
+		{math.MinInt64, "-106751d23h47m16.854775808s"},
+	}
+
+	for _, test := range tests {
+		s := formatDuration(test.value)
+		assertString(t, s, test.expected)
+		d, err := parseDuration(s)
+
+		if err != nil || d != test.value {
+			t.Errorf("Expected %s to round-trip to %v, but got %v (%v)", s, test.value, d, err)
+		}
+	}
+}
+
+func TestDurationOption(t *testing.T) {
+	var opts = struct {
+		Retention time.Duration `long:"retention" default:"30d"`
+	}{}
+
+	assertParseSuccess(t, &opts)
+
+	if opts.Retention != 30*day {
+		t.Errorf("Expected Retention to be 30d, but got %v", opts.Retention)
+	}
+
+	p := NewNamedParser("test", None)
+	p.AddGroup("Application Options", "", &opts)
+
+	opts.Retention = week
+
+	var b bytes.Buffer
+	NewIniParser(p).Write(&b, IniIncludeDefaults)
+
+	assertString(t, b.String(), "[Application Options]\nRetention = 7d\n\n")
+}
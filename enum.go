@@ -0,0 +1,127 @@
+package flags
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Enum is the interface implemented by types which only allow a fixed set of
+// values. Options of such a type (or of slices, arrays, maps, pointers or
+// optionals of such a type) only accept the values returned by Enum, which
+// are shown in the help and man page, and used for completion. The choice
+// tag takes precedence over Enum.
+type Enum interface {
+	// Enum returns the allowed values.
+	Enum() []string
+}
+
+// EnumDescriber is the interface implemented by enum types which describe
+// their values. The descriptions are shown in the help and man page, and
+// used for completion.
+type EnumDescriber interface {
+	Enum
+
+	// EnumDescription returns the description of the given allowed value.
+	EnumDescription(value string) string
+}
+
+var enumType = reflect.TypeOf((*Enum)(nil)).Elem()
+
+// enumOf returns the Enum implemented by values of type tp, or nil.
+// Pointers and interfaces are not considered enums themselves, since they
+// may be nil.
+func enumOf(tp reflect.Type) Enum {
+	if tp.Kind() == reflect.Ptr || tp.Kind() == reflect.Interface {
+		return nil
+	}
+
+	if tp.Implements(enumType) {
+		return reflect.Zero(tp).Interface().(Enum)
+	}
+
+	if reflect.PtrTo(tp).Implements(enumType) {
+		return reflect.New(tp).Interface().(Enum)
+	}
+
+	return nil
+}
+
+// enumElemOf returns the Enum implemented by the elements of type tp,
+// dereferencing slices, arrays, maps, pointers and optionals, or nil.
+func enumElemOf(tp reflect.Type) Enum {
+	for {
+		if enum := enumOf(tp); enum != nil {
+			return enum
+		}
+
+		if elem := optionalElemType(tp); elem != nil {
+			tp = elem
+			continue
+		}
+
+		switch tp.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.Ptr:
+			tp = tp.Elem()
+		default:
+			return nil
+		}
+	}
+}
+
+// isMapType returns whether tp is a map, or an optional map.
+func isMapType(tp reflect.Type) bool {
+	if elem := optionalElemType(tp); elem != nil {
+		tp = elem
+	}
+
+	return tp.Kind() == reflect.Map
+}
+
+// enumCompletions returns the values of enum starting with match.
+func enumCompletions(enum Enum, match string) []Completion {
+	var ret []Completion
+
+	for _, v := range enum.Enum() {
+		if strings.HasPrefix(v, match) {
+			ret = append(ret, Completion{
+				Item:        v,
+				Description: enumDescription(enum, v),
+			})
+		}
+	}
+
+	return ret
+}
+
+// enumDescription returns the description of the given value of enum, or an
+// empty string if enum does not describe its values.
+func enumDescription(enum Enum, value string) string {
+	if describer, ok := enum.(EnumDescriber); ok {
+		return describer.EnumDescription(value)
+	}
+
+	return ""
+}
+
+// checkEnum returns an error if retval is an enum which does not allow val.
+func checkEnum(val string, retval reflect.Value) error {
+	enum := enumOf(retval.Type())
+
+	if enum == nil {
+		return nil
+	}
+
+	values := enum.Enum()
+
+	for _, v := range values {
+		if v == val {
+			return nil
+		}
+	}
+
+	return &messageError{
+		message: func(p *Parser) string {
+			return p.Message(MsgInvalidEnumValue, val, p.joinList(values))
+		},
+	}
+}
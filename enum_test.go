@@ -0,0 +1,118 @@
+package flags
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testLevel string
+
+func (testLevel) Enum() []string {
+	return []string{"debug", "info", "error"}
+}
+
+func (testLevel) EnumDescription(value string) string {
+	switch value {
+	case "debug":
+		return "verbose output"
+	case "error":
+		return "only errors"
+	}
+
+	return ""
+}
+
+type testColor string
+
+func (*testColor) Enum() []string {
+	return []string{"red", "green"}
+}
+
+func TestEnum(t *testing.T) {
+	var opts = struct {
+		Level  testLevel            `long:"level"`
+		Colors []testColor          `long:"color"`
+		Levels map[string]testLevel `long:"levels"`
+		Choice testLevel            `long:"choice" choice:"info"`
+	}{}
+
+	assertParseSuccess(t, &opts, "--level", "debug", "--color", "red", "--color", "green", "--levels", "a:error")
+
+	assertString(t, string(opts.Level), "debug")
+
+	if !reflect.DeepEqual(opts.Colors, []testColor{"red", "green"}) {
+		t.Errorf("Unexpected colors %v", opts.Colors)
+	}
+
+	if !reflect.DeepEqual(opts.Levels, map[string]testLevel{"a": "error"}) {
+		t.Errorf("Unexpected levels %v", opts.Levels)
+	}
+
+	assertParseFail(t, ErrInvalidChoice, "Invalid value `warn' for option `"+defaultLongOptDelimiter+"level'. Allowed values are: debug, info or error",
+		&opts, "--level", "warn")
+
+	assertParseFail(t, ErrInvalidChoice, "Invalid value `blue' for option `"+defaultLongOptDelimiter+"color'. Allowed values are: red or green",
+		&opts, "--color", "blue")
+
+	assertParseFail(t, ErrMarshal, "invalid argument for flag `"+defaultLongOptDelimiter+"levels' (expected map[string]flags.testLevel): invalid value `warn', allowed values are: debug, info or error",
+		&opts, "--levels", "a:warn")
+
+	assertParseFail(t, ErrInvalidChoice, "Invalid value `debug' for option `"+defaultLongOptDelimiter+"choice'. Allowed values are: info",
+		&opts, "--choice", "debug")
+}
+
+func TestEnumHelp(t *testing.T) {
+	var opts = struct {
+		Level testLevel `long:"level" description:"The log level"`
+	}{}
+
+	p := NewNamedParser("test", None)
+	p.AddGroup("Application Options", "", &opts)
+
+	var help bytes.Buffer
+	p.WriteHelp(&help)
+
+	for _, expected := range []string{
+		defaultLongOptDelimiter + "level" + string(defaultNameArgDelimiter) + "[debug|info|error]",
+		"The log level",
+		"debug: verbose output",
+		"error: only errors",
+	} {
+		if !strings.Contains(help.String(), expected) {
+			t.Errorf("Expected help to contain %q, but got:\n%s", expected, help.String())
+		}
+	}
+
+	var man bytes.Buffer
+	p.WriteManPage(&man)
+
+	for _, expected := range []string{".br\n\\fIdebug\\fR: verbose output\n", ".br\n\\fIinfo\\fR\n"} {
+		if !strings.Contains(man.String(), expected) {
+			t.Errorf("Expected man page to contain %q, but got:\n%s", expected, man.String())
+		}
+	}
+}
+
+func TestEnumCompletion(t *testing.T) {
+	var opts = struct {
+		Level  testLevel   `long:"level"`
+		Colors []testColor `long:"color"`
+	}{}
+
+	p := NewParser(&opts, None)
+	c := &completion{parser: p}
+
+	ret := c.complete([]string{"--level", "d"})
+
+	if !reflect.DeepEqual(ret, []Completion{{Item: "debug", Description: "verbose output"}}) {
+		t.Errorf("Unexpected completions %v", ret)
+	}
+
+	ret = c.complete([]string{"--color="})
+
+	if !reflect.DeepEqual(ret, []Completion{{Item: "--color=green"}, {Item: "--color=red"}}) {
+		t.Errorf("Unexpected completions %v", ret)
+	}
+}
@@ -0,0 +1,110 @@
+package flags
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// EnvNameUpperSnake converts a long option name to an environment variable
+// name in upper snake case, e.g. dry-run, dryRun and db.dry_run are converted
+// to DRY_RUN, DRY_RUN and DB_DRY_RUN. It is the default EnvNaming of the
+// parser.
+func EnvNameUpperSnake(name string) string {
+	return strings.Map(func(r rune) rune {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return '_'
+		}
+
+		return unicode.ToUpper(r)
+	}, normalizeName(name))
+}
+
+// automaticEnvKey returns the env key derived from the long name of the
+// option if automatic env keys are enabled on the parser (see EnvPrefix), or
+// an empty string otherwise.
+func (option *Option) automaticEnvKey() string {
+	p := option.parser()
+
+	if p == nil || (p.EnvPrefix == "" && p.EnvNaming == nil) {
+		return ""
+	}
+
+	if option.LongName == "" || option.group.isBuiltinHelp || option.tag.Get("no-env") != "" {
+		return ""
+	}
+
+	naming := p.EnvNaming
+
+	if naming == nil {
+		naming = EnvNameUpperSnake
+	}
+
+	// The env namespace of a group takes the place of its namespace, it
+	// is prepended by EnvKeyWithNamespace
+	name := option.LongName
+
+	for g := option.group; g != nil; {
+		if g.Namespace != "" && g.EnvNamespace == "" {
+			name = g.Namespace + p.NamespaceDelimiter + name
+		}
+
+		switch i := g.parent.(type) {
+		case *Command:
+			g = i.Group
+		case *Group:
+			g = i
+		default:
+			g = nil
+		}
+	}
+
+	return naming(name)
+}
+
+// checkEnv reports the environment variables starting with the env prefix
+// which do not correspond to the env key of any option, as warnings or as an
+// error depending on the WarnUnknownEnv and ErrorOnUnknownEnv options.
+func (p *Parser) checkEnv() error {
+	if p.EnvPrefix == "" || (p.Options&(WarnUnknownEnv|ErrorOnUnknownEnv)) == None {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	var keys []string
+
+	p.eachOption(func(c *Command, g *Group, option *Option) {
+		if key := option.EnvKeyWithNamespace(); key != "" && !known[key] {
+			known[key] = true
+			keys = append(keys, key)
+		}
+	})
+
+	prefix := p.EnvPrefix + p.EnvNamespaceDelimiter
+	var unknown []string
+
+	for _, name := range p.envNames() {
+		if strings.HasPrefix(name, prefix) && !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+
+	sort.Strings(unknown)
+
+	for _, name := range unknown {
+		msg := p.Message(MsgUnknownEnv, name)
+
+		if c, l := closestChoice(name, keys); c != "" && float32(l)/float32(len(c)) < 0.5 {
+			msg = p.Message(MsgDidYouMean, msg, c)
+		}
+
+		if (p.Options & ErrorOnUnknownEnv) != None {
+			return newError(ErrUnknownEnv, msg)
+		}
+
+		fmt.Fprintln(p.stderr(), p.Message(MsgWarning, msg))
+	}
+
+	return nil
+}
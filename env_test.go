@@ -0,0 +1,181 @@
+package flags
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+type envPrefixOptions struct {
+	LogLevel string `long:"log-level" description:"Log level"`
+	Explicit string `long:"explicit" env:"EXPLICIT_KEY" description:"Explicit env key"`
+	Ignored  string `long:"ignored" no-env:"true" description:"Not from the environment"`
+	Short    string `short:"s" description:"Short name only"`
+
+	DB struct {
+		Host string `long:"host" description:"Database host"`
+	} `group:"Database" namespace:"db"`
+
+	Cache struct {
+		Size int `long:"size" description:"Cache size"`
+	} `group:"Cache" namespace:"cache" env-namespace:"MEMORY"`
+}
+
+func TestEnvNameUpperSnake(t *testing.T) {
+	tests := map[string]string{
+		"dry-run":     "DRY_RUN",
+		"dryRun":      "DRY_RUN",
+		"db.dry_run":  "DB_DRY_RUN",
+		"JSONOutput":  "JSON_OUTPUT",
+		"http2-ports": "HTTP2_PORTS",
+	}
+
+	for name, expected := range tests {
+		assertString(t, EnvNameUpperSnake(name), expected)
+	}
+}
+
+func TestEnvPrefix(t *testing.T) {
+	oldEnv := EnvSnapshot()
+	defer oldEnv.Restore()
+
+	os.Setenv("APP_LOG_LEVEL", "debug")
+	os.Setenv("EXPLICIT_KEY", "explicit")
+	os.Setenv("APP_EXPLICIT", "derived")
+	os.Setenv("APP_IGNORED", "ignored")
+	os.Setenv("APP_DB_HOST", "localhost")
+	os.Setenv("APP_MEMORY_SIZE", "42")
+
+	var opts envPrefixOptions
+
+	p := NewParser(&opts, None)
+	p.EnvPrefix = "APP"
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.LogLevel, "debug")
+	assertString(t, opts.Explicit, "explicit")
+	assertString(t, opts.Ignored, "")
+	assertString(t, opts.DB.Host, "localhost")
+
+	if opts.Cache.Size != 42 {
+		t.Errorf("Expected cache size 42, but got %d", opts.Cache.Size)
+	}
+
+	keys := map[string]string{
+		"log-level":  "APP_LOG_LEVEL",
+		"explicit":   "EXPLICIT_KEY",
+		"ignored":    "",
+		"db.host":    "APP_DB_HOST",
+		"cache.size": "APP_MEMORY_SIZE",
+	}
+
+	for name, key := range keys {
+		assertString(t, p.FindOptionByLongName(name).EnvKeyWithNamespace(), key)
+	}
+
+	assertString(t, p.FindOptionByShortName('s').EnvKeyWithNamespace(), "")
+}
+
+func TestEnvNaming(t *testing.T) {
+	oldEnv := EnvSnapshot()
+	defer oldEnv.Restore()
+
+	os.Setenv("log_level", "warning")
+
+	var opts envPrefixOptions
+
+	p := NewParser(&opts, None)
+	p.EnvNaming = func(name string) string {
+		return strings.ReplaceAll(name, "-", "_")
+	}
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.LogLevel, "warning")
+}
+
+func TestEnvPrefixHelp(t *testing.T) {
+	var opts struct {
+		LogLevel string `long:"log-level" description:"Log level"`
+	}
+
+	p := NewNamedParser("TestEnvPrefix", HelpFlag)
+	p.AddGroup("Application Options", "", &opts)
+	p.OptionStyle = OptionStylePOSIX
+	p.EnvPrefix = "APP"
+
+	_, err := p.ParseArgs([]string{"--help"})
+	assertError(t, err, ErrHelp, err.Error())
+
+	if !strings.Contains(err.Error(), "APP_LOG_LEVEL") {
+		t.Errorf("Expected help to show the env key, but got:\n%s", err.Error())
+	}
+
+	// The built-in help option is not bound to the environment
+	if strings.Contains(err.Error(), "APP_HELP") {
+		t.Errorf("Expected help option without env key, but got:\n%s", err.Error())
+	}
+
+	var buf bytes.Buffer
+	p.WriteManPage(&buf)
+
+	if !strings.Contains(buf.String(), "APP_LOG_LEVEL") {
+		t.Errorf("Expected man page to show the env key, but got:\n%s", buf.String())
+	}
+}
+
+func TestUnknownEnv(t *testing.T) {
+	oldEnv := EnvSnapshot()
+	defer oldEnv.Restore()
+
+	os.Setenv("APP_LOG_LEVL", "debug")
+	os.Setenv("APP_DB_HOST", "localhost")
+	os.Setenv("APPLICATION", "other")
+
+	var opts envPrefixOptions
+
+	p := NewParser(&opts, ErrorOnUnknownEnv)
+	p.EnvPrefix = "APP"
+
+	_, err := p.ParseArgs(nil)
+	assertError(t, err, ErrUnknownEnv, "unknown environment variable `APP_LOG_LEVL', did you mean `APP_LOG_LEVEL'?")
+
+	if code := p.ExitCode(err); code != ExitConfig {
+		t.Errorf("Expected exit code %d, but got %d", ExitConfig, code)
+	}
+
+	os.Setenv("APP_ZZZ", "1")
+
+	var stderr bytes.Buffer
+
+	opts = envPrefixOptions{}
+	p = NewParser(&opts, WarnUnknownEnv)
+	p.EnvPrefix = "APP"
+	p.Stderr = &stderr
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.DB.Host, "localhost")
+	assertString(t, stderr.String(), "warning: unknown environment variable `APP_LOG_LEVL', did you mean `APP_LOG_LEVEL'?\nwarning: unknown environment variable `APP_ZZZ'\n")
+
+	// Without the options, unknown variables are ignored
+	stderr.Reset()
+
+	p = NewParser(&opts, None)
+	p.EnvPrefix = "APP"
+	p.Stderr = &stderr
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, stderr.String(), "")
+}
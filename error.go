@@ -58,43 +58,63 @@ const (
 
 	// ErrInvalidTag indicates an invalid tag or invalid use of an existing tag
 	ErrInvalidTag
+
+	// ErrValidation indicates that the Validate method of a group or
+	// command failed.
+	ErrValidation
+
+	// ErrUnknownEnv indicates an environment variable with the env prefix
+	// of the parser which does not correspond to any option.
+	ErrUnknownEnv
 )
 
+// String returns the English name of the error type. Use
+// Parser.ErrorTypeString to obtain the name from the parser's message
+// catalog.
 func (e ErrorType) String() string {
+	msg, _ := EnglishMessages.Message(e.messageKey())
+	return msg
+}
+
+func (e ErrorType) messageKey() MessageKey {
 	switch e {
 	case ErrUnknown:
-		return "unknown"
+		return MsgErrUnknown
 	case ErrExpectedArgument:
-		return "expected argument"
+		return MsgErrExpectedArgument
 	case ErrUnknownFlag:
-		return "unknown flag"
+		return MsgErrUnknownFlag
 	case ErrUnknownGroup:
-		return "unknown group"
+		return MsgErrUnknownGroup
 	case ErrMarshal:
-		return "marshal"
+		return MsgErrMarshal
 	case ErrHelp:
-		return "help"
+		return MsgErrHelp
 	case ErrNoArgumentForBool:
-		return "no argument for bool"
+		return MsgErrNoArgumentForBool
 	case ErrRequired:
-		return "required"
+		return MsgErrRequired
 	case ErrShortNameTooLong:
-		return "short name too long"
+		return MsgErrShortNameTooLong
 	case ErrDuplicatedFlag:
-		return "duplicated flag"
+		return MsgErrDuplicatedFlag
 	case ErrTag:
-		return "tag"
+		return MsgErrTag
 	case ErrCommandRequired:
-		return "command required"
+		return MsgErrCommandRequired
 	case ErrUnknownCommand:
-		return "unknown command"
+		return MsgErrUnknownCommand
 	case ErrInvalidChoice:
-		return "invalid choice"
+		return MsgErrInvalidChoice
 	case ErrInvalidTag:
-		return "invalid tag"
+		return MsgErrInvalidTag
+	case ErrValidation:
+		return MsgErrValidation
+	case ErrUnknownEnv:
+		return MsgErrUnknownEnv
 	}
 
-	return "unrecognized error type"
+	return MsgErrUnrecognized
 }
 
 func (e ErrorType) Error() string {
@@ -109,6 +129,9 @@ type Error struct {
 
 	// The error message
 	Message string
+
+	// The underlying error, if any (e.g. the error returned by a Validator)
+	Err error
 }
 
 // Error returns the error's message
@@ -116,6 +139,11 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the underlying error, if any.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
 func newError(tp ErrorType, message string) *Error {
 	return &Error{
 		Type:    tp,
@@ -0,0 +1,154 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Exit codes used by Parser.ExitCode. They follow the conventions of the
+// BSD sysexits.h header.
+const (
+	// ExitOK indicates successful termination.
+	ExitOK = 0
+
+	// ExitFailure indicates a generic failure.
+	ExitFailure = 1
+
+	// ExitUsage indicates that the command was used incorrectly, e.g. with
+	// an unknown flag, a missing required flag or a bad argument.
+	ExitUsage = 64
+
+	// ExitDataErr indicates that the input data was incorrect in some way.
+	ExitDataErr = 65
+
+	// ExitSoftware indicates an internal software error, such as an invalid
+	// option definition.
+	ExitSoftware = 70
+
+	// ExitConfig indicates that something was found in an unconfigured or
+	// misconfigured state, such as an invalid ini file.
+	ExitConfig = 78
+)
+
+// ExitCoder is an interface which can be implemented by errors to carry
+// their own exit code. Errors returned from Commander.Execute which
+// implement ExitCoder determine the exit code used by Parser.Exit and Main.
+type ExitCoder interface {
+	// ExitCode returns the exit code with which the process should
+	// terminate.
+	ExitCode() int
+}
+
+// osExit terminates the process, it can be replaced for testing.
+var osExit = os.Exit
+
+func defaultExitCode(tp ErrorType) int {
+	switch tp {
+	case ErrHelp:
+		return ExitOK
+	case ErrExpectedArgument, ErrUnknownFlag, ErrNoArgumentForBool,
+		ErrRequired, ErrCommandRequired, ErrUnknownCommand, ErrInvalidChoice,
+		ErrValidation:
+		return ExitUsage
+	case ErrMarshal:
+		return ExitDataErr
+	case ErrUnknownGroup, ErrUnknownEnv:
+		return ExitConfig
+	case ErrShortNameTooLong, ErrDuplicatedFlag, ErrTag, ErrInvalidTag:
+		return ExitSoftware
+	}
+
+	return ExitFailure
+}
+
+func isUsageError(tp ErrorType) bool {
+	return defaultExitCode(tp) == ExitUsage || tp == ErrMarshal
+}
+
+// ExitCode returns the exit code corresponding to the given error. A nil
+// error maps to ExitOK. Errors implementing ExitCoder provide their own exit
+// code, also when wrapped in an Error (e.g. errors returned by a Validator).
+// Other errors of type Error are mapped using the parser's ExitCodes,
+// falling back to the sysexits conventions. Errors of type IniError or
+// DotenvError map to ExitConfig and all other errors map to ExitFailure.
+func (p *Parser) ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var coder ExitCoder
+
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	var flagsErr *Error
+
+	if errors.As(err, &flagsErr) {
+		if code, ok := p.ExitCodes[flagsErr.Type]; ok {
+			return code
+		}
+
+		return defaultExitCode(flagsErr.Type)
+	}
+
+	var iniErr *IniError
+
+	if errors.As(err, &iniErr) {
+		return ExitConfig
+	}
+
+	var dotenvErr *DotenvError
+
+	if errors.As(err, &dotenvErr) {
+		return ExitConfig
+	}
+
+	return ExitFailure
+}
+
+// Exit terminates the process with the exit code corresponding to err (see
+// ExitCode). Unless already done by the PrintErrors option, the error is
+// first written out. For usage errors, a hint on how to obtain the help
+// message is also written when the HelpFlag option is set.
+func (p *Parser) Exit(err error) {
+	if err != nil {
+		if (p.Options & PrintErrors) == None {
+			p.writeError(err)
+		}
+
+		var flagsErr *Error
+
+		if errors.As(err, &flagsErr) && isUsageError(flagsErr.Type) && (p.Options&HelpFlag) != None {
+			fmt.Fprintln(p.stderr(), p.Message(MsgTryHelp, p.activeCommandName()+" "+p.longOptDelimiter()+"help"))
+		}
+	}
+
+	osExit(p.ExitCode(err))
+}
+
+// Main is a convenience function which parses the command line arguments
+// from os.Args using the provided parser. When parsing fails, or the
+// executed command returns an error, the process is terminated using
+// Parser.Exit. Otherwise, the remaining command line arguments are
+// returned.
+func Main(p *Parser) []string {
+	args, err := p.Parse()
+
+	if err != nil {
+		p.Exit(err)
+	}
+
+	return args
+}
+
+func (p *Parser) activeCommandName() string {
+	name := p.Name
+
+	for c := p.Active; c != nil; c = c.Active {
+		name += " " + c.Name
+	}
+
+	return name
+}
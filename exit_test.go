@@ -0,0 +1,112 @@
+package flags
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return "command failed"
+}
+
+func (e *exitCodeError) ExitCode() int {
+	return e.code
+}
+
+func TestExitCode(t *testing.T) {
+	p := NewNamedParser("test", Default)
+
+	var tests = []struct {
+		err      error
+		expected int
+	}{
+		{nil, ExitOK},
+		{newError(ErrHelp, "help"), ExitOK},
+		{newError(ErrRequired, "required"), ExitUsage},
+		{newError(ErrUnknownFlag, "unknown"), ExitUsage},
+		{newError(ErrMarshal, "marshal"), ExitDataErr},
+		{newError(ErrDuplicatedFlag, "duplicated"), ExitSoftware},
+		{&IniError{Message: "ini"}, ExitConfig},
+		{&exitCodeError{code: 3}, 3},
+		{&Error{Type: ErrValidation, Message: "wrapped", Err: &exitCodeError{code: 4}}, 4},
+		{errors.New("generic"), ExitFailure},
+	}
+
+	for _, test := range tests {
+		if code := p.ExitCode(test.err); code != test.expected {
+			t.Errorf("Expected exit code %d for %v, but got %d", test.expected, test.err, code)
+		}
+	}
+}
+
+type exitCodeValidator struct {
+	Value string `long:"value"`
+}
+
+func (v *exitCodeValidator) Validate() error {
+	return &exitCodeError{code: 5}
+}
+
+func TestExitCodeValidator(t *testing.T) {
+	var opts exitCodeValidator
+
+	p := NewParser(&opts, None)
+	_, err := p.ParseArgs(nil)
+
+	assertError(t, err, ErrValidation, "invalid options in group `Application Options': command failed")
+
+	if code := p.ExitCode(err); code != 5 {
+		t.Errorf("Expected exit code 5, but got %d", code)
+	}
+}
+
+func TestExitCodeOverride(t *testing.T) {
+	p := NewNamedParser("test", Default)
+	p.ExitCodes = map[ErrorType]int{
+		ErrRequired: 2,
+	}
+
+	if code := p.ExitCode(newError(ErrRequired, "required")); code != 2 {
+		t.Errorf("Expected exit code 2, but got %d", code)
+	}
+
+	if code := p.ExitCode(newError(ErrUnknownFlag, "unknown")); code != ExitUsage {
+		t.Errorf("Expected exit code %d, but got %d", ExitUsage, code)
+	}
+}
+
+func TestExit(t *testing.T) {
+	var opts struct {
+		Value bool `short:"v"`
+	}
+
+	code := -1
+	prevExit := osExit
+	osExit = func(c int) { code = c }
+	defer func() { osExit = prevExit }()
+
+	var stderr bytes.Buffer
+
+	p := NewNamedParser("test", Default&^PrintErrors)
+	p.AddGroup("Application Options", "", &opts)
+	p.Stderr = &stderr
+
+	_, err := p.ParseArgs([]string{"-x"})
+
+	if err == nil {
+		t.Fatal("Expected error for unknown flag")
+	}
+
+	p.Exit(err)
+
+	if code != ExitUsage {
+		t.Errorf("Expected exit code %d, but got %d", ExitUsage, code)
+	}
+
+	assertString(t, stderr.String(), "unknown flag `x'\nTry 'test "+defaultLongOptDelimiter+"help' for more information.\n")
+}
@@ -0,0 +1,212 @@
+package flags
+
+import (
+	"errors"
+	"strings"
+)
+
+// expander expands references to environment variables and option values,
+// see ExpandVariables.
+type expander struct {
+	ini *IniParser
+
+	// The ini file being parsed, or nil when expanding default values
+	file *ini
+
+	// The options whose values are being resolved, to detect cycles
+	resolving map[*Option]bool
+}
+
+func newExpander(p *Parser, file *ini) *expander {
+	return &expander{
+		ini:       NewIniParser(p),
+		file:      file,
+		resolving: make(map[*Option]bool),
+	}
+}
+
+// expand replaces the references in value by the values they refer to.
+// References to environment variables are written as ${NAME}, references to
+// other options as ${section.key} and a literal dollar sign as $$.
+func (e *expander) expand(value string) (string, error) {
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+
+	var ret strings.Builder
+	rest := value
+
+	for {
+		i := strings.IndexByte(rest, '$')
+
+		if i < 0 {
+			ret.WriteString(rest)
+			break
+		}
+
+		ret.WriteString(rest[:i])
+		rest = rest[i+1:]
+
+		switch {
+		case strings.HasPrefix(rest, "$"):
+			ret.WriteByte('$')
+			rest = rest[1:]
+		case strings.HasPrefix(rest, "{"):
+			end := strings.IndexByte(rest, '}')
+
+			if end < 0 {
+				return "", errors.New(e.ini.parser.Message(MsgUnterminatedVariable, value))
+			}
+
+			v, err := e.resolve(rest[1:end])
+
+			if err != nil {
+				return "", err
+			}
+
+			ret.WriteString(v)
+			rest = rest[end+1:]
+		default:
+			ret.WriteByte('$')
+		}
+	}
+
+	return ret.String(), nil
+}
+
+// resolve returns the value of the variable with the given name. Names
+// containing a dot refer to an option in an ini section (group or command),
+// other names refer to environment variables.
+func (e *expander) resolve(name string) (string, error) {
+	p := e.ini.parser
+
+	if !strings.Contains(name, ".") {
+		value, _ := p.lookupEnv(name)
+		return value, nil
+	}
+
+	opt := e.option(name)
+
+	if opt == nil {
+		return "", errors.New(p.Message(MsgUnknownVariable, name))
+	}
+
+	if e.resolving[opt] {
+		return "", errors.New(p.Message(MsgCyclicVariable, name))
+	}
+
+	e.resolving[opt] = true
+	defer delete(e.resolving, opt)
+
+	if e.file != nil {
+		if value, ok := e.fileValue(opt); ok {
+			return e.expand(value)
+		}
+	}
+
+	return e.optionValue(opt)
+}
+
+// option returns the option referred to by name, which consists of the name
+// of an ini section and the name of the option separated by a dot. Since
+// section names may contain dots themselves (e.g. command.Group), all the
+// dots are tried in turn.
+func (e *expander) option(name string) *Option {
+	for i := 0; i < len(name); i++ {
+		if name[i] != '.' {
+			continue
+		}
+
+		if opt := e.ini.optionByIniName(e.ini.matchingGroups(name[:i]), name[i+1:]); opt != nil {
+			return opt
+		}
+	}
+
+	return nil
+}
+
+// fileValue returns the value given to opt in the ini file being parsed.
+// If the option is given a value more than once, possibly in different
+// sections, the value on the last line is used.
+func (e *expander) fileValue(opt *Option) (string, bool) {
+	var ret *iniValue
+
+	for name, section := range e.file.Sections {
+		groups := e.ini.matchingGroups(name)
+
+		for i := range section {
+			inival := &section[i]
+
+			if (ret == nil || inival.LineNumber > ret.LineNumber) && e.ini.optionByIniName(groups, inival.Name) == opt {
+				ret = inival
+			}
+		}
+	}
+
+	if ret == nil {
+		return "", false
+	}
+
+	return ret.Value, true
+}
+
+// expandDefaults expands the variables in the given default values of the
+// option if the ExpandVariables option is set.
+func (option *Option) expandDefaults(defs []string) ([]string, error) {
+	p := option.parser()
+
+	if len(defs) == 0 || (p.Options&ExpandVariables) == None {
+		return defs, nil
+	}
+
+	e := newExpander(p, nil)
+	e.resolving[option] = true
+
+	ret := make([]string, len(defs))
+
+	for i, d := range defs {
+		v, err := e.expand(d)
+
+		if err != nil {
+			return nil, p.newError(ErrInvalidTag, MsgInvalidDefault, option, err)
+		}
+
+		ret[i] = v
+	}
+
+	return ret, nil
+}
+
+// optionValue returns the value of opt if it has been set, or otherwise its
+// expanded default value.
+func (e *expander) optionValue(opt *Option) (string, error) {
+	if opt.source == SourceNone || opt.source == SourceDefault {
+		defs, err := opt.defaults()
+
+		if err != nil {
+			return "", err
+		}
+
+		if len(defs) != 0 {
+			expanded := make([]string, len(defs))
+
+			for i, d := range defs {
+				if expanded[i], err = e.expand(d); err != nil {
+					return "", err
+				}
+			}
+
+			if opt.splitsValues() {
+				return strings.Join(expanded, opt.Delim), nil
+			}
+
+			return expanded[len(expanded)-1], nil
+		}
+	}
+
+	if opt.splitsValues() {
+		return opt.joinedValue(), nil
+	}
+
+	return convertToString(opt.value, opt.tag)
+}
@@ -0,0 +1,206 @@
+package flags
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+type expandOptions struct {
+	Root  string `long:"root" default:"${TEST_EXPAND_HOME}/.cache"`
+	Cache string `long:"cache" default:"${Application Options.root}/app"`
+	Price string `long:"price" default:"$$5"`
+}
+
+func TestExpandVariablesDefault(t *testing.T) {
+	oldEnv := EnvSnapshot()
+	defer oldEnv.Restore()
+
+	os.Setenv("TEST_EXPAND_HOME", "/home/me")
+
+	var opts expandOptions
+
+	p := NewParser(&opts, ExpandVariables)
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.Root, "/home/me/.cache")
+	assertString(t, opts.Cache, "/home/me/.cache/app")
+	assertString(t, opts.Price, "$5")
+
+	// References use the value given on the command line
+	opts = expandOptions{}
+
+	if _, err := p.ParseArgs([]string{defaultLongOptDelimiter + "root" + string(defaultNameArgDelimiter) + "/tmp"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.Root, "/tmp")
+	assertString(t, opts.Cache, "/tmp/app")
+}
+
+func TestExpandVariablesDisabled(t *testing.T) {
+	var opts expandOptions
+
+	assertParseSuccess(t, &opts)
+
+	assertString(t, opts.Root, "${TEST_EXPAND_HOME}/.cache")
+	assertString(t, opts.Price, "$$5")
+}
+
+func TestExpandVariablesDefaultErrors(t *testing.T) {
+	tests := []struct {
+		data interface{}
+		msg  string
+	}{
+		{
+			&struct {
+				A string `long:"a" default:"${Application Options.b}"`
+				B string `long:"b" default:"${Application Options.a}"`
+			}{},
+			"invalid default value for flag `%sb': cyclic reference to variable `Application Options.b'",
+		},
+		{
+			&struct {
+				A string `long:"a" default:"${TEST_EXPAND"`
+			}{},
+			"invalid default value for flag `%sa': unterminated variable reference in `${TEST_EXPAND'",
+		},
+		{
+			&struct {
+				A string `long:"a" default:"${Application Options.missing}"`
+			}{},
+			"invalid default value for flag `%sa': unknown variable `Application Options.missing'",
+		},
+	}
+
+	for _, test := range tests {
+		p := NewParser(test.data, ExpandVariables)
+		_, err := p.ParseArgs(nil)
+
+		assertError(t, err, ErrInvalidTag, fmt.Sprintf(test.msg, defaultLongOptDelimiter))
+	}
+}
+
+func TestExpandVariablesIni(t *testing.T) {
+	oldEnv := EnvSnapshot()
+	defer oldEnv.Restore()
+
+	os.Setenv("TEST_EXPAND_DATA", "/data")
+
+	var opts struct {
+		Path  string `long:"path"`
+		Log   string `long:"log"`
+		Price string `long:"price"`
+	}
+
+	p := NewParser(&opts, ExpandVariables)
+
+	inip := NewIniParser(p)
+	inistr := "[Application Options]\nlog = ${Application Options.path}/log\npath = ${TEST_EXPAND_DATA}/app\nprice = $$5\n"
+
+	if err := inip.Parse(strings.NewReader(inistr)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.Path, "/data/app")
+	assertString(t, opts.Log, "/data/app/log")
+	assertString(t, opts.Price, "$5")
+}
+
+func TestExpandVariablesIniLastValue(t *testing.T) {
+	var opts struct {
+		Path string `long:"path"`
+		Log  string `long:"log"`
+	}
+
+	// Values without a section apply to all groups, the reference uses
+	// the value on the last line
+	inistr := "path = /a\n[Application Options]\npath = /b\nlog = ${Application Options.path}/log\n"
+
+	for i := 0; i < 10; i++ {
+		p := NewParser(&opts, ExpandVariables)
+
+		if err := NewIniParser(p).Parse(strings.NewReader(inistr)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		assertString(t, opts.Log, "/b/log")
+	}
+}
+
+func TestExpandVariablesNestedSection(t *testing.T) {
+	var opts struct {
+		Cmd struct {
+			Options struct {
+				Root string `long:"root"`
+			} `group:"Options"`
+
+			Data string `long:"data" default:"${cmd.Options.root}/data"`
+		} `command:"cmd"`
+	}
+
+	p := NewParser(&opts, ExpandVariables)
+
+	if _, err := p.ParseArgs([]string{"cmd", defaultLongOptDelimiter + "root" + string(defaultNameArgDelimiter) + "/srv"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.Cmd.Data, "/srv/data")
+}
+
+func TestExpandVariablesIniErrors(t *testing.T) {
+	tests := []struct {
+		ini  string
+		line uint
+		msg  string
+	}{
+		{
+			"[Application Options]\npath = /tmp\nlog = ${Application Options.log}/x\n",
+			3,
+			"cyclic reference to variable `Application Options.log'",
+		},
+		{
+			"[Application Options]\nlog = ${Application Options.path}\npath = ${Application Options.log}\n",
+			0,
+			"cyclic reference to variable `Application Options.",
+		},
+		{
+			"[Application Options]\npath = ${TEST_EXPAND_DATA\n",
+			2,
+			"unterminated variable reference in `${TEST_EXPAND_DATA'",
+		},
+		{
+			"[Application Options]\n\npath = ${other.path}\n",
+			3,
+			"unknown variable `other.path'",
+		},
+	}
+
+	for _, test := range tests {
+		var opts struct {
+			Path string `long:"path"`
+			Log  string `long:"log"`
+		}
+
+		p := NewParser(&opts, ExpandVariables)
+		err := NewIniParser(p).Parse(strings.NewReader(test.ini))
+
+		inierr, ok := err.(*IniError)
+
+		if !ok {
+			t.Fatalf("Expected *IniError, but got %v", err)
+		}
+
+		if !strings.HasPrefix(inierr.Message, test.msg) {
+			t.Errorf("Expected error %q, but got %q", test.msg, inierr.Message)
+		}
+
+		if test.line != 0 && inierr.LineNumber != test.line {
+			t.Errorf("Expected error on line %d, but got %d", test.line, inierr.LineNumber)
+		}
+	}
+}
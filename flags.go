@@ -25,6 +25,8 @@ The following features are supported in go-flags:
     Supports -I/usr/include -I=/usr/include -I /usr/include option argument specification
     Supports multiple short options -aux
     Supports all primitive go types (string, int{8..64}, uint{8..64}, float)
+    Supports net.IP, net.IPNet, netip.AddrPort, url.URL, regexp.Regexp, time.Time and time.Location
+    Supports byte sizes with SI and IEC units (ByteSize) and durations with day and week units
     Supports same option multiple times (can store in slice or last option counts)
     Supports maps
     Supports function callbacks
@@ -36,7 +38,7 @@ Additional features specific to Windows:
     Windows-style options with arguments use a colon as the delimiter
     Modify generated help message with Windows-style / options
     Windows style options can be disabled at build time using the "forceposix"
-    build tag
+    build tag, or at runtime by setting Parser.OptionStyle
 
 
 Basic usage
@@ -57,6 +59,12 @@ resulting value of Verbose will be {[true, true, true]}.
 Slice options work exactly the same as primitive type options, except that
 whenever the option is encountered, a value is appended to the slice.
 
+Array options (e.g. [3]uint8) are filled one element at a time, and need
+to be given exactly as many values as the array has elements, either by
+specifying the option multiple times or by using the delim tag (e.g.
+--color=255,128,0 with delim:","). Specifying too many or too few values
+results in an error.
+
 Map options from string to primitive type are also supported. On the command
 line, you specify the value for such an option as key:value. For example
 
@@ -67,17 +75,89 @@ line, you specify the value for such an option as key:value. For example
 Then, the AuthorInfo map can be filled with something like
 -a name:Jesse -a "surname:van den Kieboom".
 
+Types which only allow a fixed set of values can implement the Enum
+interface, instead of repeating the choice tag on every option of the type:
+
+    type Level string
+
+    func (Level) Enum() []string {
+        return []string{"debug", "info", "error"}
+    }
+
+Options of such a type (or of slices, maps or pointers of such a type) only
+accept the returned values, which are shown in the help and man page and
+used for completion. Implement EnumDescriber to describe each value.
+
+To distinguish an option which was not specified from an option which was
+specified with the zero value of its type, use the generic Optional type:
+
+    type Options struct {
+        Retries flags.Optional[int] `long:"retries"`
+    }
+
+Optional.IsSet reports whether the value was set (e.g. with --retries=0),
+and Optional.Source reports whether it was set from the default value, the
+environment, an ini file or the command line.
+
+Defaults which can only be computed at runtime, such as the number of CPUs or
+the home directory of the user, can be provided by implementing the
+DefaultProvider interface on the options struct of a group or command. The
+provided defaults replace those of the default tag, and are shown in the help
+and written to ini files in the same way.
+
+When the ExpandVariables option is set, default values and ini values can
+refer to environment variables as ${NAME}, and to the values of other options
+as ${section.key}, where section is the name of a group or command and key the
+name of the option as used in ini files. Use $$ for a literal dollar sign, e.g.
+
+    Cache string `long:"cache" default:"${HOME}/.cache/app"`
+
+Instead of adding an env tag to every option, set the EnvPrefix of the parser
+to derive the env keys of all options from their long names, e.g. with
+EnvPrefix "APP" the option --log-level is set from $APP_LOG_LEVEL. To catch
+typos in such variables, the WarnUnknownEnv and ErrorOnUnknownEnv options
+report variables with the prefix which do not correspond to any option.
+
+Environment variables can also be loaded from dotenv (.env) files using
+Parser.LoadDotenvFile. The loaded variables are used in the same way as those
+of the environment, without modifying the environment of the process.
+
+Options can also have a struct type (or a slice of structs, to allow the
+option to occur multiple times). The fields of the struct are then set from
+comma separated key=value pairs, for example:
+
+    type Database struct {
+        Host    string `required:"true"`
+        Port    int    `default:"5432"`
+        SSLMode string `key:"sslmode" choice:"disable" choice:"require"`
+    }
+
+    DB Database `long:"db"`
+
+can be filled with --db host=localhost,sslmode=require. The key of a field
+is given by its key tag, or otherwise by its lower case name. The default,
+choice and required tags are supported on the fields of the struct.
+
 Finally, for full control over the conversion between command line argument
 values and options, user defined types can choose to implement the Marshaler
-and Unmarshaler interfaces.
+and Unmarshaler interfaces. Types implementing encoding.TextMarshaler and
+encoding.TextUnmarshaler (such as net.IP or big.Int), or flag.Value, are
+converted using these interfaces when they do not implement Marshaler and
+Unmarshaler.
 
 
 Available field tags
 
 The following is a list of tags for struct fields supported by go-flags:
 
-    short:            the short name of the option (single character)
-    long:             the long name of the option
+    short:            the short name of the option (single character). Can be
+                      specified multiple times, in which case the additional
+                      names are aliases of the first
+    long:             the long name of the option. Can be specified multiple
+                      times, in which case the additional names are aliases
+                      of the first
+    short-alias:      an additional short name of the option (optional)
+    long-alias:       an additional long name of the option (optional)
     required:         if non empty, makes the option required to appear on the command
                       line. If a required option is not present, the parser will
                       return ErrRequired (optional)
@@ -106,15 +186,47 @@ The following is a list of tags for struct fields supported by go-flags:
     env-delim:      the 'env' default value from environment is split into
                     multiple values with the given delimiter string, use with
                     slices and maps (optional)
-    value-name:     the name of the argument value (to be shown in the help)
+    no-env:         if non-empty, no env key is derived for this option
+                    when the parser has an EnvPrefix (optional)
+    env-file:       the name of an environment variable containing the path
+                    of a file from which the value of the option is read,
+                    if the env variable is not set. Implies secret
                     (optional)
+    file:           the long name of an additional option taking the path of
+                    a file from which the value of the option is read, e.g.
+                    file:"password-file". A trailing newline is removed from
+                    the value. No env key is derived for the additional
+                    option (use env-file instead). Implies secret (optional)
+    secret:         if non-empty, the value of the option is masked in the
+                    help message, errors and ini files (optional)
+    delim:          each value of the option is split into multiple values
+                    with the given delimiter string, e.g. --tags=a,b,c with
+                    delim:",". Values containing the delimiter can be
+                    enclosed in double quotes, or the delimiter can be
+                    escaped with a backslash. Use with slices, arrays and
+                    maps (optional)
+    value-name:     the name of the argument value (to be shown in the help).
+                    Can be specified multiple times to name the arguments of
+                    an option with nargs (optional)
+    nargs:          the number of arguments consumed by each occurrence of
+                    the option, e.g. --point X Y with nargs:"2". A range
+                    (e.g. nargs:"1-3") makes the arguments beyond the first
+                    number optional. Use with slices and arrays, whose
+                    elements are set from the arguments, or structs, whose
+                    fields are set from the arguments in order (optional)
     choice:         limits the values for an option to a set of values.
                     Repeat this tag once for each allowable value.
                     e.g. `long:"animal" choice:"cat" choice:"dog"`
     hidden:         if non-empty, the option is not visible in the help or man page.
+    prefix:         the prefix character with which the option is specified
+                    instead of the delimiters of the option style, e.g.
+                    `short:"x" prefix:"+"` for +x. The prefix character
+                    needs to be one of the parser's OptionPrefixes (optional)
 
     base: a base (radix) used to convert strings to integer values, the
           default base is 10 (i.e. decimal) (optional)
+    layout: the layout used to parse and format time.Time values (see
+            time.Parse), the default layout is time.RFC3339 (optional)
 
     ini-name:       the explicit ini option name (optional)
     no-ini:         if non-empty this field is ignored as an ini option
@@ -143,6 +255,8 @@ The following is a list of tags for struct fields supported by go-flags:
                           positional command line arguments into (in order
                           of the fields). If a field has a slice type,
                           then all remaining arguments will be added to it.
+                          A field with an array type consumes exactly as
+                          many arguments as the array has elements.
                           Positional arguments are optional by default,
                           unless the "required" tag is specified together
                           with the "positional-args" tag. The "required" tag
@@ -174,6 +288,10 @@ There are currently three ways to specify option groups.
     3. Add a struct field to the top-level options annotated with the
        group:"group-name" tag.
 
+Flags defined using the standard library flag package can be added as an
+option group using AddFlagSet. Conversely, ExportFlagSet defines the options
+of a group in a flag.FlagSet.
+
 
 
 Commands
@@ -211,6 +329,12 @@ However, if the -v flag is defined on the add command, then the first of
 the two examples above would fail since the -v flag is not defined before
 the add command.
 
+Rules involving several options (e.g. either a token, or a user and a
+password) can be checked by implementing the Validator interface on the
+options struct of a group or command. After the command line has been
+parsed, Validate is called on the groups of all the active commands, and
+an error it returns is reported as an Error of type ErrValidation.
+
 
 Completion
 
@@ -0,0 +1,194 @@
+package flags
+
+import (
+	"flag"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+)
+
+// flagValue wraps a flag.Value so that it can be used as the value of an
+// option.
+type flagValue struct {
+	value flag.Value
+}
+
+// UnmarshalFlag sets the wrapped flag.Value.
+func (f *flagValue) UnmarshalFlag(value string) error {
+	return f.value.Set(value)
+}
+
+// MarshalFlag returns the string representation of the wrapped flag.Value.
+func (f flagValue) MarshalFlag() (string, error) {
+	return f.value.String(), nil
+}
+
+type boolFlag interface {
+	flag.Value
+	IsBoolFlag() bool
+}
+
+// boolFlagFunc sets a boolean flag imported from a flag.FlagSet.
+type boolFlagFunc func(value string) error
+
+// isBoolFlag returns whether the option was imported from a boolean flag.
+func (option *Option) isBoolFlag() bool {
+	return option.value.Type() == reflect.TypeOf(boolFlagFunc(nil))
+}
+
+// AddFlagSet adds a new group to the command containing an option for every
+// flag defined in the given flag set. Flags with a single character name
+// are added as short options, all other flags as long options. Setting an
+// option sets the corresponding flag.Value. Boolean flags (i.e. flags whose
+// value has an IsBoolFlag method returning true) take an optional argument
+// (e.g. --flag=false) and are set to true when specified without one.
+func (c *Command) AddFlagSet(shortDescription string, longDescription string, fs *flag.FlagSet) *Group {
+	group := newFlagSetGroup(shortDescription, longDescription, fs)
+
+	group.parent = c
+	c.groups = append(c.groups, group)
+
+	return group
+}
+
+// AddFlagSet adds a new group to the group containing an option for every
+// flag defined in the given flag set. See Command.AddFlagSet for more
+// information.
+func (g *Group) AddFlagSet(shortDescription string, longDescription string, fs *flag.FlagSet) *Group {
+	group := newFlagSetGroup(shortDescription, longDescription, fs)
+
+	group.parent = g
+	g.groups = append(g.groups, group)
+
+	return group
+}
+
+func newFlagSetGroup(shortDescription string, longDescription string, fs *flag.FlagSet) *Group {
+	group := newGroup(shortDescription, longDescription, nil)
+
+	fs.VisitAll(func(f *flag.Flag) {
+		valueName, usage := flag.UnquoteUsage(f)
+
+		option := &Option{
+			Description: usage,
+			ValueName:   valueName,
+			group:       group,
+			tag:         newMultiTag(""),
+		}
+
+		if utf8.RuneCountInString(f.Name) == 1 {
+			option.ShortName, _ = utf8.DecodeRuneInString(f.Name)
+		} else {
+			option.LongName = f.Name
+		}
+
+		if b, ok := f.Value.(boolFlag); ok && b.IsBoolFlag() {
+			option.OptionalArgument = true
+			option.OptionalValue = []string{"true"}
+			option.value = reflect.ValueOf(boolFlagFunc(b.Set))
+		} else {
+			option.value = reflect.New(reflect.TypeOf(flagValue{})).Elem()
+			option.value.Set(reflect.ValueOf(flagValue{value: f.Value}))
+		}
+
+		option.field = reflect.StructField{
+			Name: f.Name,
+			Type: option.value.Type(),
+		}
+
+		option.updateDefaultLiteral()
+		group.options = append(group.options, option)
+	})
+
+	return group
+}
+
+// optionFlag wraps an option so that it can be used as a flag.Value.
+type optionFlag struct {
+	option *Option
+}
+
+// String returns the string representation of the option value.
+func (f *optionFlag) String() string {
+	if f == nil || f.option == nil {
+		return ""
+	}
+
+	s, _ := convertToString(f.option.value, f.option.tag)
+	return s
+}
+
+// Set sets the option value.
+func (f *optionFlag) Set(value string) error {
+	if f.option.isFunc() && f.option.isBool() {
+		if b, err := strconv.ParseBool(value); err != nil || !b {
+			return err
+		}
+
+		return f.option.Set(nil)
+	}
+
+	if err := f.option.Set(&value); err != nil {
+		return err
+	}
+
+	f.option.setSource(SourceCommandLine)
+	return nil
+}
+
+// IsBoolFlag returns whether the option does not take an argument.
+func (f *optionFlag) IsBoolFlag() bool {
+	return !f.option.canArgument() || f.option.isBoolFlag()
+}
+
+// ExportFlagSet defines a flag in the given flag set for every option in the
+// group and its subgroups. Options are registered with all of their short
+// and long (including namespace) names. The default values of the options
+// are applied before the flags are defined, and parsing the flag set sets
+// the options as if they were specified on the command line. An error is
+// returned when one of the names is already defined in the flag set.
+func (g *Group) ExportFlagSet(fs *flag.FlagSet) error {
+	var err error
+
+	g.eachGroup(func(grp *Group) {
+		for _, option := range grp.options {
+			if err != nil {
+				return
+			}
+
+			var names []string
+
+			for _, name := range option.shortNames() {
+				names = append(names, string(name))
+			}
+
+			names = append(names, option.longNamesWithNamespace()...)
+
+			for _, name := range names {
+				if fs.Lookup(name) != nil {
+					err = option.parser().newError(ErrDuplicatedFlag, MsgFlagRedefined, name)
+					return
+				}
+			}
+
+			if err = option.clearDefault(); err != nil {
+				if _, ok := err.(*Error); !ok {
+					err = option.parser().marshalError(option, err)
+				}
+
+				return
+			}
+
+			option.clearReferenceBeforeSet = true
+			option.updateDefaultLiteral()
+
+			value := &optionFlag{option: option}
+
+			for _, name := range names {
+				fs.Var(value, name, option.Description)
+			}
+		}
+	})
+
+	return err
+}
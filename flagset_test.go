@@ -0,0 +1,195 @@
+package flags
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddFlagSet(t *testing.T) {
+	var opts struct {
+		Verbose bool `short:"v" long:"verbose"`
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	port := fs.Int("port", 8080, "the `number` of the port to listen on")
+	debug := fs.Bool("d", false, "enable debugging")
+	timeout := fs.Duration("timeout", time.Second, "request timeout")
+
+	p := NewParser(&opts, None)
+	p.AddFlagSet("Library Options", "", fs)
+
+	ret, err := p.ParseArgs([]string{"-v", "--port", "80", "-d", "--timeout=5s", "arg"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertStringArray(t, ret, []string{"arg"})
+
+	if !opts.Verbose {
+		t.Errorf("Expected Verbose to be true")
+	}
+
+	if *port != 80 {
+		t.Errorf("Expected port to be 80, but got %v", *port)
+	}
+
+	if !*debug {
+		t.Errorf("Expected d to be true")
+	}
+
+	if *timeout != 5*time.Second {
+		t.Errorf("Expected timeout to be 5s, but got %v", *timeout)
+	}
+
+	_, err = p.ParseArgs([]string{"--port", "abc"})
+	assertError(t, err, ErrMarshal, "invalid argument for flag `"+defaultLongOptDelimiter+"port"+"': parse error")
+}
+
+func TestAddFlagSetBoolArgument(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	cache := fs.Bool("cache", true, "enable caching")
+
+	p := NewParser(&struct{}{}, None)
+	p.AddFlagSet("Library Options", "", fs)
+
+	ret, err := p.ParseArgs([]string{"--cache=false", "arg"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertStringArray(t, ret, []string{"arg"})
+
+	if *cache {
+		t.Errorf("Expected cache to be false")
+	}
+
+	// Without an argument, the flag does not consume the next argument
+	ret, err = p.ParseArgs([]string{"--cache", "false"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertStringArray(t, ret, []string{"false"})
+
+	if !*cache {
+		t.Errorf("Expected cache to be true")
+	}
+
+	_, err = p.ParseArgs([]string{"--cache=maybe"})
+	assertError(t, err, ErrMarshal, "invalid argument for flag `"+defaultLongOptDelimiter+"cache"+"': parse error")
+}
+
+func TestAddFlagSetHelp(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	fs.Int("port", 8080, "the `number` of the port to listen on")
+	fs.Bool("d", false, "enable debugging")
+
+	p := NewNamedParser("test", None)
+	p.AddFlagSet("Library Options", "", fs)
+
+	var b bytes.Buffer
+	p.WriteHelp(&b)
+
+	for _, expected := range []string{
+		"Library Options:",
+		string(defaultShortOptDelimiter) + "d" + "   ",
+		defaultLongOptDelimiter + "port" + string(defaultNameArgDelimiter) + "number",
+		"the number of the port to listen on (default: 8080)",
+		"enable debugging",
+	} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("Expected help to contain %q, but got:\n%s", expected, b.String())
+		}
+	}
+}
+
+func TestExportFlagSet(t *testing.T) {
+	var opts struct {
+		Verbose bool     `short:"v" long:"verbose" description:"Show verbose output"`
+		Name    string   `long:"name" default:"world"`
+		Values  []int    `long:"value" default:"1" default:"2"`
+		Call    func()   `long:"call"`
+		Sub     struct{} `group:"Sub"`
+	}
+
+	called := false
+	opts.Call = func() {
+		called = true
+	}
+
+	p := NewParser(&opts, None)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+
+	if err := p.ExportFlagSet(fs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.Name, "world")
+	assertString(t, fs.Lookup("name").DefValue, "world")
+
+	if err := fs.Parse([]string{"-v", "-value", "3", "-value=4", "-call", "arg"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertStringArray(t, fs.Args(), []string{"arg"})
+
+	if !opts.Verbose {
+		t.Errorf("Expected Verbose to be true")
+	}
+
+	if !called {
+		t.Errorf("Expected Call to be called")
+	}
+
+	if len(opts.Values) != 2 || opts.Values[0] != 3 || opts.Values[1] != 4 {
+		t.Errorf("Expected Values to be [3 4], but got %v", opts.Values)
+	}
+
+	if fs.Lookup("verbose") == nil || fs.Lookup("v") == nil {
+		t.Errorf("Expected both short and long name to be defined")
+	}
+
+	if err := fs.Parse([]string{"-verbose=false"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if opts.Verbose {
+		t.Errorf("Expected Verbose to be false")
+	}
+}
+
+func TestExportFlagSetRedefined(t *testing.T) {
+	var opts struct {
+		Verbose bool   `short:"v" long:"verbose"`
+		Name    string `long:"name"`
+	}
+
+	p := NewParser(&opts, None)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("name", "", "an existing flag")
+
+	err := p.ExportFlagSet(fs)
+	assertError(t, err, ErrDuplicatedFlag, "flag `name' is already defined in the flag set")
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+
+	if err := p.ExportFlagSet(fs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err = p.ExportFlagSet(fs)
+	assertError(t, err, ErrDuplicatedFlag, "flag `v' is already defined in the flag set")
+}
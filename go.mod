@@ -1,5 +1,5 @@
 module github.com/jessevdk/go-flags
 
-go 1.15
+go 1.18
 
 require golang.org/x/sys v0.0.0-20210320140829-1e4c9ba3b0c4
@@ -7,6 +7,7 @@ package flags
 import (
 	"errors"
 	"reflect"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 )
@@ -52,6 +53,9 @@ type Group struct {
 	// Whether the group represents the built-in help group
 	isBuiltinHelp bool
 
+	// Whether the group represents the default application options group
+	isApplicationOptions bool
+
 	data interface{}
 }
 
@@ -120,18 +124,31 @@ func (g *Group) findOption(matcher func(*Option) bool) (option *Option) {
 }
 
 // FindOptionByLongName finds an option that is part of the group, or any of its
-// subgroups, by matching its long name (including the option namespace).
+// subgroups, by matching its long name or long aliases (including the option
+// namespace).
 func (g *Group) FindOptionByLongName(longName string) *Option {
 	return g.findOption(func(option *Option) bool {
-		return option.LongNameWithNamespace() == longName
+		for _, name := range option.longNamesWithNamespace() {
+			if name == longName {
+				return true
+			}
+		}
+
+		return false
 	})
 }
 
 // FindOptionByShortName finds an option that is part of the group, or any of
-// its subgroups, by matching its short name.
+// its subgroups, by matching its short name or short aliases.
 func (g *Group) FindOptionByShortName(shortName rune) *Option {
 	return g.findOption(func(option *Option) bool {
-		return option.ShortName == shortName
+		for _, name := range option.shortNames() {
+			if name == shortName {
+				return true
+			}
+		}
+
+		return false
 	})
 }
 
@@ -160,14 +177,18 @@ func (g *Group) optionByName(name string, namematch func(*Option, string) bool)
 				prio = 3
 			}
 
-			if name == opt.LongNameWithNamespace() && prio < 2 {
-				retopt = opt
-				prio = 2
+			for _, long := range opt.longNamesWithNamespace() {
+				if name == long && prio < 2 {
+					retopt = opt
+					prio = 2
+				}
 			}
 
-			if opt.ShortName != 0 && name == string(opt.ShortName) && prio < 1 {
-				retopt = opt
-				prio = 1
+			for _, short := range opt.shortNames() {
+				if name == string(short) && prio < 1 {
+					retopt = opt
+					prio = 1
+				}
 			}
 		}
 	})
@@ -175,6 +196,25 @@ func (g *Group) optionByName(name string, namematch func(*Option, string) bool)
 	return retopt
 }
 
+// parser returns the parser at the root of the group hierarchy, or nil if the
+// group has not been added to a parser.
+func (g *Group) parser() *Parser {
+	for g != nil {
+		switch i := g.parent.(type) {
+		case *Parser:
+			return i
+		case *Command:
+			g = i.Group
+		case *Group:
+			g = i
+		default:
+			g = nil
+		}
+	}
+
+	return nil
+}
+
 func (g *Group) showInHelp() bool {
 	if g.Hidden {
 		return false
@@ -199,6 +239,29 @@ func isStringFalsy(s string) bool {
 	return s == "" || s == "false" || s == "no" || s == "0"
 }
 
+// parseNargsTag parses the value of the nargs tag, which is either a fixed
+// number of arguments (e.g. "2") or a range (e.g. "1-3").
+func parseNargsTag(tag string) (int, int, error) {
+	if tag == "" {
+		return 0, 0, nil
+	}
+
+	rng := strings.SplitN(tag, "-", 2)
+
+	nargs, err := strconv.Atoi(rng[0])
+	maximum := nargs
+
+	if err == nil && len(rng) == 2 {
+		maximum, err = strconv.Atoi(rng[1])
+	}
+
+	if err != nil || nargs < 1 || maximum < nargs {
+		return 0, 0, newErrorf(ErrInvalidTag, "invalid nargs `%s'", tag)
+	}
+
+	return nargs, maximum, nil
+}
+
 func (g *Group) scanStruct(realval reflect.Value, sfield *reflect.StructField, handler scanHandler) error {
 	stype := realval.Type()
 
@@ -229,15 +292,25 @@ func (g *Group) scanStruct(realval reflect.Value, sfield *reflect.StructField, h
 			continue
 		}
 
-		// Dive deep into structs or pointers to structs
+		// The first long and short names are the names of the option, any
+		// further names are aliases
+		longnames := append(mtag.GetMany("long"), mtag.GetMany("long-alias")...)
+		shortnames := append(mtag.GetMany("short"), mtag.GetMany("short-alias")...)
+
+		// Need at least either a short or long name
+		isOption := len(longnames) != 0 || len(shortnames) != 0 || mtag.Get("ini-name") != ""
+
+		// Dive deep into structs or pointers to structs, unless the field is
+		// an option itself (struct valued options are parsed from key=value
+		// pairs)
 		kind := field.Type.Kind()
 		fld := realval.Field(i)
 
-		if kind == reflect.Struct {
+		if !isOption && kind == reflect.Struct {
 			if err := g.scanStruct(fld, &field, handler); err != nil {
 				return err
 			}
-		} else if kind == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct {
+		} else if !isOption && kind == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct {
 			flagCountBefore := len(g.options) + len(g.groups)
 
 			if fld.IsNil() {
@@ -253,24 +326,38 @@ func (g *Group) scanStruct(realval reflect.Value, sfield *reflect.StructField, h
 			}
 		}
 
-		longname := mtag.Get("long")
-		shortname := mtag.Get("short")
-
-		// Need at least either a short or long name
-		if longname == "" && shortname == "" && mtag.Get("ini-name") == "" {
+		if !isOption {
 			continue
 		}
 
+		var shorts []rune
+
+		for _, shortname := range shortnames {
+			rc := utf8.RuneCountInString(shortname)
+
+			if rc > 1 {
+				return newErrorf(ErrShortNameTooLong,
+					"short names can only be 1 character long, not `%s'",
+					shortname)
+
+			} else if rc == 1 {
+				short, _ := utf8.DecodeRuneInString(shortname)
+				shorts = append(shorts, short)
+			}
+		}
+
 		short := rune(0)
-		rc := utf8.RuneCountInString(shortname)
+		var shortAliases []rune
 
-		if rc > 1 {
-			return newErrorf(ErrShortNameTooLong,
-				"short names can only be 1 character long, not `%s'",
-				shortname)
+		if len(shorts) != 0 {
+			short, shortAliases = shorts[0], shorts[1:]
+		}
 
-		} else if rc == 1 {
-			short, _ = utf8.DecodeRuneInString(shortname)
+		longname := ""
+		var longAliases []string
+
+		if len(longnames) != 0 {
+			longname, longAliases = longnames[0], longnames[1:]
 		}
 
 		description := mtag.Get("description")
@@ -283,22 +370,60 @@ func (g *Group) scanStruct(realval reflect.Value, sfield *reflect.StructField, h
 		optional := !isStringFalsy(mtag.Get("optional"))
 		required := !isStringFalsy(mtag.Get("required"))
 		choices := mtag.GetMany("choice")
+
+		// The values of map options are validated when they are converted,
+		// since the choices apply to the complete key:value argument
+		if enum := enumElemOf(field.Type); len(choices) == 0 && enum != nil && !isMapType(field.Type) {
+			choices = enum.Enum()
+		}
 		hidden := !isStringFalsy(mtag.Get("hidden"))
 
+		// Values read from files are considered secrets
+		fileName := mtag.Get("file")
+		envFileKey := mtag.Get("env-file")
+		secret := !isStringFalsy(mtag.Get("secret")) || fileName != "" || envFileKey != ""
+
+		prefix := rune(0)
+
+		if tag := mtag.Get("prefix"); tag != "" {
+			if utf8.RuneCountInString(tag) != 1 {
+				return newErrorf(ErrInvalidTag,
+					"prefix can only be 1 character long, not `%s'",
+					tag)
+			}
+
+			prefix, _ = utf8.DecodeRuneInString(tag)
+		}
+
+		nargs, nargsMaximum, err := parseNargsTag(mtag.Get("nargs"))
+
+		if err != nil {
+			return err
+		}
+
 		option := &Option{
 			Description:      description,
 			ShortName:        short,
 			LongName:         longname,
+			ShortAliases:     shortAliases,
+			LongAliases:      longAliases,
 			Default:          def,
 			EnvDefaultKey:    mtag.Get("env"),
 			EnvDefaultDelim:  mtag.Get("env-delim"),
+			EnvFileKey:       envFileKey,
+			Delim:            mtag.Get("delim"),
 			OptionalArgument: optional,
 			OptionalValue:    optionalValue,
 			Required:         required,
 			ValueName:        valueName,
+			ValueNames:       mtag.GetMany("value-name"),
+			Nargs:            nargs,
+			NargsMaximum:     nargsMaximum,
 			DefaultMask:      defaultMask,
 			Choices:          choices,
 			Hidden:           hidden,
+			Secret:           secret,
+			Prefix:           prefix,
 
 			group: g,
 
@@ -307,6 +432,28 @@ func (g *Group) scanStruct(realval reflect.Value, sfield *reflect.StructField, h
 			tag:   mtag,
 		}
 
+		if tp := structOptionType(field.Type); tp != nil {
+			if _, err := structKeys(tp); err != nil {
+				return err
+			}
+		}
+
+		if option.Delim != "" && kind != reflect.Slice && kind != reflect.Array && kind != reflect.Map {
+			return newErrorf(ErrInvalidTag,
+				"delim tag can only be used with slice, array or map options, not `%s'",
+				option.shortAndLongName())
+		}
+
+		if err := option.checkNargs(); err != nil {
+			return err
+		}
+
+		if option.ValueName == "" && field.Type.Kind() != reflect.Map {
+			if tp := specialType(field.Type); tp != nil {
+				option.ValueName = specialValueName(tp)
+			}
+		}
+
 		if option.isBool() && option.Default != nil {
 			return newErrorf(ErrInvalidTag,
 				"boolean flag `%s' may not have default values, they always default to `false' and can only be turned on",
@@ -314,21 +461,33 @@ func (g *Group) scanStruct(realval reflect.Value, sfield *reflect.StructField, h
 		}
 
 		g.options = append(g.options, option)
+
+		if fileName != "" {
+			g.options = append(g.options, option.fileOption(fileName))
+		}
 	}
 
 	return nil
 }
 
 func (g *Group) checkForDuplicateFlags() *Error {
-	shortNames := make(map[rune]*Option)
+	shortNames := make(map[string]*Option)
 	longNames := make(map[string]*Option)
 
+	p := g.parser()
+
 	var duplicateError *Error
 
 	g.eachGroup(func(g *Group) {
 		for _, option := range g.options {
-			if option.LongName != "" {
-				longName := option.LongNameWithNamespace()
+			prefix := ""
+
+			if option.Prefix != 0 {
+				prefix = string(option.Prefix)
+			}
+
+			for _, name := range option.longNamesWithNamespace() {
+				longName := prefix + p.longNameKey(name)
 
 				if otherOption, ok := longNames[longName]; ok {
 					duplicateError = newErrorf(ErrDuplicatedFlag, "option `%s' uses the same long name as option `%s'", option, otherOption)
@@ -336,12 +495,14 @@ func (g *Group) checkForDuplicateFlags() *Error {
 				}
 				longNames[longName] = option
 			}
-			if option.ShortName != 0 {
-				if otherOption, ok := shortNames[option.ShortName]; ok {
+			for _, name := range option.shortNames() {
+				shortName := prefix + string(name)
+
+				if otherOption, ok := shortNames[shortName]; ok {
 					duplicateError = newErrorf(ErrDuplicatedFlag, "option `%s' uses the same short name as option `%s'", option, otherOption)
 					return
 				}
-				shortNames[option.ShortName] = option
+				shortNames[shortName] = option
 			}
 		}
 	})
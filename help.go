@@ -90,11 +90,12 @@ func (p *Parser) getAlignmentInfo() alignmentInfo {
 				ret.hasShort = true
 			}
 
-			if len(info.ValueName) > 0 {
+			if len(info.helpValueName()) > 0 {
 				ret.hasValueName = true
 			}
 
-			l := info.LongNameWithNamespace() + info.ValueName
+			shortAliases, longAliases := info.helpAliases()
+			l := info.LongNameWithNamespace() + shortAliases + longAliases + info.helpValueName()
 
 			if len(info.Choices) != 0 {
 				l += "[" + strings.Join(info.Choices, "|") + "]"
@@ -178,9 +179,12 @@ func (p *Parser) writeHelpOption(writer *bufio.Writer, option *Option, info alig
 
 	line.WriteString(strings.Repeat(" ", prefix))
 
+	shortAliases, longAliases := option.helpAliases()
+
 	if option.ShortName != 0 {
-		line.WriteRune(defaultShortOptDelimiter)
+		line.WriteString(option.shortDelimiter())
 		line.WriteRune(option.ShortName)
+		line.WriteString(shortAliases)
 	} else if info.hasShort {
 		line.WriteString("  ")
 	}
@@ -194,12 +198,17 @@ func (p *Parser) writeHelpOption(writer *bufio.Writer, option *Option, info alig
 			line.WriteString("  ")
 		}
 
-		line.WriteString(defaultLongOptDelimiter)
+		line.WriteString(option.longDelimiter())
 		line.WriteString(option.LongNameWithNamespace())
+		line.WriteString(longAliases)
 	}
 
-	if option.canArgument() {
-		line.WriteRune(defaultNameArgDelimiter)
+	if option.canArgument() && option.Nargs != 0 {
+		// The arguments of options with multiple arguments are given as
+		// separate arguments
+		line.WriteString(" " + option.helpValueName())
+	} else if option.canArgument() && !option.isBoolFlag() {
+		line.WriteRune(option.nameArgDelimiter())
 
 		if len(option.ValueName) > 0 {
 			line.WriteString(option.ValueName)
@@ -213,7 +222,7 @@ func (p *Parser) writeHelpOption(writer *bufio.Writer, option *Option, info alig
 	written := line.Len()
 	line.WriteTo(writer)
 
-	if option.Description != "" {
+	if option.description() != "" {
 		dw := descstart - written
 		writer.WriteString(strings.Repeat(" ", dw))
 
@@ -223,8 +232,8 @@ func (p *Parser) writeHelpOption(writer *bufio.Writer, option *Option, info alig
 			if option.DefaultMask != "-" {
 				def = option.DefaultMask
 			}
-		} else {
-			def = option.defaultLiteral
+		} else if len(option.defaultLiteral) != 0 {
+			def = option.maskSecret(option.defaultLiteral)
 		}
 
 		var envDef string
@@ -238,12 +247,24 @@ func (p *Parser) writeHelpOption(writer *bufio.Writer, option *Option, info alig
 			envDef = fmt.Sprintf(" [%s]", envPrintable)
 		}
 
-		var desc string
+		desc := option.description()
+
+		if tp := structOptionType(option.value.Type()); tp != nil {
+			if keys, err := structKeys(tp); err == nil && len(keys) != 0 {
+				desc += " " + p.Message(MsgStructKeys, strings.Join(structKeyNames(keys), ", "))
+			}
+		}
 
 		if def != "" {
-			desc = fmt.Sprintf("%s (default: %v)%s", option.Description, def, envDef)
+			desc = fmt.Sprintf("%s %s%s", desc, p.Message(MsgDefault, def), envDef)
 		} else {
-			desc = option.Description + envDef
+			desc += envDef
+		}
+
+		for _, choice := range option.Choices {
+			if d := option.choiceDescription(choice); d != "" {
+				desc += "\n" + choice + ": " + d
+			}
 		}
 
 		writer.WriteString(wrapText(desc,
@@ -292,7 +313,7 @@ func (p *Parser) WriteHelp(writer io.Writer) {
 	}
 
 	if p.Name != "" {
-		wr.WriteString("Usage:\n")
+		wr.WriteString(p.Message(MsgUsage) + "\n")
 		wr.WriteString(" ")
 
 		allcmd := p.Command
@@ -304,12 +325,12 @@ func (p *Parser) WriteHelp(writer io.Writer) {
 				if len(p.Usage) != 0 {
 					usage = p.Usage
 				} else if p.Options&HelpFlag != 0 {
-					usage = "[OPTIONS]"
+					usage = p.Message(MsgUsageOptions)
 				}
 			} else if us, ok := allcmd.data.(Usage); ok {
 				usage = us.Usage()
 			} else if allcmd.hasHelpOptions() {
-				usage = fmt.Sprintf("[%s-OPTIONS]", allcmd.Name)
+				usage = p.Message(MsgUsageCommandOptions, allcmd.Name)
 			}
 
 			if len(usage) != 0 {
@@ -405,7 +426,7 @@ func (p *Parser) WriteHelp(writer io.Writer) {
 				}
 
 				if printcmd {
-					fmt.Fprintf(wr, "\n[%s command options]\n", c.Name)
+					fmt.Fprintf(wr, "\n%s\n", p.Message(MsgCommandOptions, c.Name))
 					aligninfo.indent = true
 					printcmd = false
 				}
@@ -417,7 +438,7 @@ func (p *Parser) WriteHelp(writer io.Writer) {
 						wr.WriteString("    ")
 					}
 
-					fmt.Fprintf(wr, "%s:\n", grp.ShortDescription)
+					fmt.Fprintf(wr, "%s:\n", p.groupDescription(grp))
 					first = false
 				}
 
@@ -434,9 +455,9 @@ func (p *Parser) WriteHelp(writer io.Writer) {
 
 		if len(args) > 0 {
 			if c == p.Command {
-				fmt.Fprintf(wr, "\nArguments:\n")
+				fmt.Fprintf(wr, "\n%s\n", p.Message(MsgArguments))
 			} else {
-				fmt.Fprintf(wr, "\n[%s command arguments]\n", c.Name)
+				fmt.Fprintf(wr, "\n%s\n", p.Message(MsgCommandArguments, c.Name))
 			}
 
 			descStart := aligninfo.descriptionStart() + paddingBeforeOption
@@ -475,7 +496,7 @@ func (p *Parser) WriteHelp(writer io.Writer) {
 		maxnamelen := maxCommandLength(scommands)
 
 		fmt.Fprintln(wr)
-		fmt.Fprintln(wr, "Available commands:")
+		fmt.Fprintln(wr, p.Message(MsgAvailableCommands))
 
 		for _, c := range scommands {
 			fmt.Fprintf(wr, "  %s", c.Name)
@@ -485,7 +506,7 @@ func (p *Parser) WriteHelp(writer io.Writer) {
 				fmt.Fprintf(wr, "%s  %s", pad, c.ShortDescription)
 
 				if len(c.Aliases) > 0 {
-					fmt.Fprintf(wr, " (aliases: %s)", strings.Join(c.Aliases, ", "))
+					fmt.Fprintf(wr, " %s", p.Message(MsgAliases, strings.Join(c.Aliases, ", ")))
 				}
 
 			}
@@ -497,6 +518,18 @@ func (p *Parser) WriteHelp(writer io.Writer) {
 	wr.Flush()
 }
 
+// groupDescription returns the short description of a group as shown in the
+// help, translating the descriptions of the built-in groups.
+func (p *Parser) groupDescription(grp *Group) string {
+	if grp.isBuiltinHelp {
+		return p.Message(MsgHelpOptions)
+	} else if grp.isApplicationOptions {
+		return p.Message(MsgApplicationOptions)
+	}
+
+	return grp.ShortDescription
+}
+
 // WroteHelp is a helper to test the error from ParseArgs() to
 // determine if the help message was written. It is safe to
 // call without first checking that error is nil.
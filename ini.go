@@ -130,6 +130,12 @@ func (i *IniParser) ParseFile(filename string) error {
 // namespacing notation (i.e [subcommand.Options]). Group section names are
 // matched case insensitive.
 //
+// Unless ParseAsDefaults is set, the values are validated (see Validator)
+// right after the ini file has been parsed, before any command line
+// arguments are applied. An ini file whose values are completed by command
+// line arguments should therefore be parsed with ParseAsDefaults, in which
+// case the values are validated by ParseArgs.
+//
 // The returned errors can be of the type flags.Error or flags.IniError.
 func (i *IniParser) Parse(reader io.Reader) error {
 	ini, err := readIni(reader, "")
@@ -244,8 +250,21 @@ func writeGroupIni(cmd *Command, group *Group, namespace string, writer io.Write
 		commentOption := (options&(IniIncludeDefaults|IniCommentDefaults)) == IniIncludeDefaults|IniCommentDefaults && option.valueIsDefault()
 
 		kind := val.Type().Kind()
-		switch kind {
-		case reflect.Slice:
+
+		// Values which marshal themselves are written as a single value
+		if isMarshaler(val) {
+			kind = reflect.String
+		}
+
+		switch {
+		case option.Secret:
+			// Secrets are masked, and commented out so that the mask is
+			// not read back as the value
+			writeOption(writer, oname, reflect.String, "", secretMask, true, false)
+		case option.splitsValues() && val.Len() != 0:
+			// Delimited values are written as a single value
+			writeOption(writer, oname, reflect.String, "", option.joinedValue(), commentOption, option.iniQuote)
+		case kind == reflect.Slice || kind == reflect.Array:
 			kind = val.Type().Elem().Kind()
 
 			if val.Len() == 0 {
@@ -257,7 +276,7 @@ func writeGroupIni(cmd *Command, group *Group, namespace string, writer io.Write
 					writeOption(writer, oname, kind, "", v, commentOption, option.iniQuote)
 				}
 			}
-		case reflect.Map:
+		case kind == reflect.Map:
 			kind = val.Type().Elem().Kind()
 
 			if val.Len() == 0 {
@@ -496,6 +515,26 @@ func (i *IniParser) matchingGroups(name string) []*Group {
 	return nil
 }
 
+// optionByIniName returns the option with the given name in one of groups,
+// or nil if there is no such option or it cannot be set from ini files.
+func (i *IniParser) optionByIniName(groups []*Group, name string) *Option {
+	for _, group := range groups {
+		opt := group.optionByName(name, func(o *Option, n string) bool {
+			return strings.ToLower(o.tag.Get("ini-name")) == strings.ToLower(n)
+		})
+
+		if opt != nil && len(opt.tag.Get("no-ini")) != 0 {
+			opt = nil
+		}
+
+		if opt != nil {
+			return opt
+		}
+	}
+
+	return nil
+}
+
 func (i *IniParser) parse(ini *ini) error {
 	p := i.parser
 
@@ -504,39 +543,30 @@ func (i *IniParser) parse(ini *ini) error {
 	})
 
 	var quotesLookup = make(map[*Option]bool)
+	var expand *expander
+
+	if (p.Options & ExpandVariables) != None {
+		expand = newExpander(p, ini)
+	}
 
 	for name, section := range ini.Sections {
 		groups := i.matchingGroups(name)
 
 		if len(groups) == 0 {
 			if (p.Options & IgnoreUnknown) == None {
-				return newErrorf(ErrUnknownGroup, "could not find option group `%s'", name)
+				return p.newError(ErrUnknownGroup, MsgUnknownGroup, name)
 			}
 
 			continue
 		}
 
 		for _, inival := range section {
-			var opt *Option
-
-			for _, group := range groups {
-				opt = group.optionByName(inival.Name, func(o *Option, n string) bool {
-					return strings.ToLower(o.tag.Get("ini-name")) == strings.ToLower(n)
-				})
-
-				if opt != nil && len(opt.tag.Get("no-ini")) != 0 {
-					opt = nil
-				}
-
-				if opt != nil {
-					break
-				}
-			}
+			opt := i.optionByIniName(groups, inival.Name)
 
 			if opt == nil {
 				if (p.Options & IgnoreUnknown) == None {
 					return &IniError{
-						Message:    fmt.Sprintf("unknown option: %s", inival.Name),
+						Message:    p.Message(MsgUnknownIniOption, inival.Name),
 						File:       ini.File,
 						LineNumber: inival.LineNumber,
 					}
@@ -550,12 +580,26 @@ func (i *IniParser) parse(ini *ini) error {
 				continue
 			}
 
+			if expand != nil {
+				value, err := expand.expand(inival.Value)
+
+				if err != nil {
+					return &IniError{
+						Message:    err.Error(),
+						File:       ini.File,
+						LineNumber: inival.LineNumber,
+					}
+				}
+
+				inival.Value = value
+			}
+
 			pval := &inival.Value
 
 			if !opt.canArgument() && len(inival.Value) == 0 {
 				pval = nil
 			} else {
-				if opt.value.Type().Kind() == reflect.Map {
+				if opt.value.Type().Kind() == reflect.Map && !opt.isUnmarshaler() && !opt.splitsValues() {
 					parts := strings.SplitN(inival.Value, ":", 2)
 
 					// only handle unquoting
@@ -587,14 +631,20 @@ func (i *IniParser) parse(ini *ini) error {
 				err = opt.Set(pval)
 			}
 
+			if _, ok := err.(*Error); err != nil && !ok && opt.Secret {
+				err = p.marshalError(opt, err)
+			}
+
 			if err != nil {
 				return &IniError{
-					Message:    err.Error(),
+					Message:    p.errorMessage(err),
 					File:       ini.File,
 					LineNumber: inival.LineNumber,
 				}
 			}
 
+			opt.setSource(SourceIni)
+
 			// Defaults from ini files take precendence over defaults from parser
 			opt.preventDefault = true
 
@@ -609,6 +659,17 @@ func (i *IniParser) parse(ini *ini) error {
 
 	for opt, quoted := range quotesLookup {
 		opt.iniQuote = quoted
+
+		if err := opt.checkLength(); err != nil {
+			return &IniError{
+				Message: err.Error(),
+				File:    ini.File,
+			}
+		}
+	}
+
+	if !i.ParseAsDefaults {
+		return p.validate()
 	}
 
 	return nil
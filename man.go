@@ -49,7 +49,7 @@ func formatForMan(wr io.Writer, s string, quoter func(s string) string) {
 	}
 }
 
-func writeManPageOptions(wr io.Writer, grp *Group) {
+func writeManPageOptions(wr io.Writer, p *Parser, grp *Group) {
 	grp.eachGroup(func(group *Group) {
 		if !group.showInHelp() {
 			return
@@ -58,7 +58,7 @@ func writeManPageOptions(wr io.Writer, grp *Group) {
 		// If the parent (grp) has any subgroups, display their descriptions as
 		// subsection headers similar to the output of --help.
 		if group.ShortDescription != "" && len(grp.groups) > 0 {
-			fmt.Fprintf(wr, ".SS %s\n", group.ShortDescription)
+			fmt.Fprintf(wr, ".SS %s\n", p.groupDescription(group))
 
 			if group.LongDescription != "" {
 				formatForMan(wr, group.LongDescription, manQuoteLines)
@@ -74,51 +74,77 @@ func writeManPageOptions(wr io.Writer, grp *Group) {
 			fmt.Fprintln(wr, ".TP")
 			fmt.Fprintf(wr, "\\fB")
 
-			if opt.ShortName != 0 {
-				fmt.Fprintf(wr, "\\fB\\-%c\\fR", opt.ShortName)
+			shortDelimiter, longDelimiter := "\\-", "\\-\\-"
+
+			if opt.Prefix != 0 {
+				shortDelimiter = manQuote(string(opt.Prefix))
+				longDelimiter = shortDelimiter
+			}
+
+			for i, name := range opt.shortNames() {
+				if i != 0 {
+					fmt.Fprintf(wr, ", ")
+				}
+
+				fmt.Fprintf(wr, "\\fB%s%c\\fR", shortDelimiter, name)
 			}
 
-			if len(opt.LongName) != 0 {
-				if opt.ShortName != 0 {
+			for i, name := range opt.longNamesWithNamespace() {
+				if i != 0 || opt.ShortName != 0 {
 					fmt.Fprintf(wr, ", ")
 				}
 
-				fmt.Fprintf(wr, "\\fB\\-\\-%s\\fR", manQuote(opt.LongNameWithNamespace()))
+				fmt.Fprintf(wr, "\\fB%s%s\\fR", longDelimiter, manQuote(name))
 			}
 
-			if len(opt.ValueName) != 0 || opt.OptionalArgument {
+			if len(opt.helpValueName()) != 0 || opt.OptionalArgument {
 				if opt.OptionalArgument {
 					fmt.Fprintf(wr, " [\\fI%s=%s\\fR]", manQuote(opt.ValueName), manQuote(strings.Join(quoteV(opt.OptionalValue), ", ")))
 				} else {
-					fmt.Fprintf(wr, " \\fI%s\\fR", manQuote(opt.ValueName))
+					fmt.Fprintf(wr, " \\fI%s\\fR", manQuote(opt.helpValueName()))
 				}
 			}
 
-			if len(opt.Default) != 0 {
-				fmt.Fprintf(wr, " <default: \\fI%s\\fR>", manQuote(strings.Join(quoteV(opt.Default), ", ")))
+			if defs, _ := opt.defaults(); len(defs) != 0 && opt.Secret {
+				fmt.Fprintf(wr, " <%s: \\fI%s\\fR>", manQuote(p.Message(MsgManDefault)), manQuote(secretMask))
+			} else if len(defs) != 0 {
+				fmt.Fprintf(wr, " <%s: \\fI%s\\fR>", manQuote(p.Message(MsgManDefault)), manQuote(strings.Join(quoteV(defs), ", ")))
 			} else if len(opt.EnvKeyWithNamespace()) != 0 {
 				if runtime.GOOS == "windows" {
-					fmt.Fprintf(wr, " <default: \\fI%%%s%%\\fR>", manQuote(opt.EnvKeyWithNamespace()))
+					fmt.Fprintf(wr, " <%s: \\fI%%%s%%\\fR>", manQuote(p.Message(MsgManDefault)), manQuote(opt.EnvKeyWithNamespace()))
 				} else {
-					fmt.Fprintf(wr, " <default: \\fI$%s\\fR>", manQuote(opt.EnvKeyWithNamespace()))
+					fmt.Fprintf(wr, " <%s: \\fI$%s\\fR>", manQuote(p.Message(MsgManDefault)), manQuote(opt.EnvKeyWithNamespace()))
 				}
 			}
 
 			if opt.Required {
-				fmt.Fprintf(wr, " (\\fIrequired\\fR)")
+				fmt.Fprintf(wr, " (\\fI%s\\fR)", manQuote(p.Message(MsgManRequired)))
 			}
 
 			fmt.Fprintln(wr, "\\fP")
 
-			if len(opt.Description) != 0 {
-				formatForMan(wr, opt.Description, manQuoteLines)
+			if desc := opt.description(); len(desc) != 0 {
+				formatForMan(wr, desc, manQuoteLines)
 				fmt.Fprintln(wr, "")
 			}
+
+			// List the values of enum options
+			if enumElemOf(opt.value.Type()) != nil {
+				for _, choice := range opt.Choices {
+					fmt.Fprintf(wr, ".br\n\\fI%s\\fR", manQuote(choice))
+
+					if d := opt.choiceDescription(choice); d != "" {
+						fmt.Fprintf(wr, ": %s", manQuote(d))
+					}
+
+					fmt.Fprintln(wr, "")
+				}
+			}
 		}
 	})
 }
 
-func writeManPageSubcommands(wr io.Writer, name string, usagePrefix string, root *Command) {
+func writeManPageSubcommands(wr io.Writer, p *Parser, name string, usagePrefix string, root *Command) {
 	commands := root.sortedVisibleCommands()
 
 	for _, c := range commands {
@@ -134,11 +160,11 @@ func writeManPageSubcommands(wr io.Writer, name string, usagePrefix string, root
 			nn = c.Name
 		}
 
-		writeManPageCommand(wr, nn, usagePrefix, c)
+		writeManPageCommand(wr, p, nn, usagePrefix, c)
 	}
 }
 
-func writeManPageCommand(wr io.Writer, name string, usagePrefix string, command *Command) {
+func writeManPageCommand(wr io.Writer, p *Parser, name string, usagePrefix string, command *Command) {
 	fmt.Fprintf(wr, ".SS %s\n", name)
 	fmt.Fprintln(wr, command.ShortDescription)
 
@@ -164,21 +190,21 @@ func writeManPageCommand(wr io.Writer, name string, usagePrefix string, command
 	if us, ok := command.data.(Usage); ok {
 		usage = us.Usage()
 	} else if command.hasHelpOptions() {
-		usage = fmt.Sprintf("[%s-OPTIONS]", command.Name)
+		usage = p.Message(MsgUsageCommandOptions, command.Name)
 	}
 
 	var nextPrefix = pre
 	if len(usage) > 0 {
-		fmt.Fprintf(wr, "\n\\fBUsage\\fP: %s %s\n.TP\n", manQuote(pre), manQuote(usage))
+		fmt.Fprintf(wr, "\n\\fB%s\\fP: %s %s\n.TP\n", manQuote(p.Message(MsgManUsage)), manQuote(pre), manQuote(usage))
 		nextPrefix = pre + " " + usage
 	}
 
 	if len(command.Aliases) > 0 {
-		fmt.Fprintf(wr, "\n\\fBAliases\\fP: %s\n\n", manQuote(strings.Join(command.Aliases, ", ")))
+		fmt.Fprintf(wr, "\n\\fB%s\\fP: %s\n\n", manQuote(p.Message(MsgManAliases)), manQuote(strings.Join(command.Aliases, ", ")))
 	}
 
-	writeManPageOptions(wr, command.Group)
-	writeManPageSubcommands(wr, name, nextPrefix, command)
+	writeManPageOptions(wr, p, command.Group)
+	writeManPageSubcommands(wr, p, name, nextPrefix, command)
 }
 
 // WriteManPage writes a basic man page in groff format to the specified
@@ -195,29 +221,29 @@ func (p *Parser) WriteManPage(wr io.Writer) {
 	}
 
 	fmt.Fprintf(wr, ".TH %s 1 \"%s\"\n", manQuote(p.Name), t.Format("2 January 2006"))
-	fmt.Fprintln(wr, ".SH NAME")
+	fmt.Fprintf(wr, ".SH %s\n", p.Message(MsgManName))
 	fmt.Fprintf(wr, "%s \\- %s\n", manQuote(p.Name), manQuoteLines(p.ShortDescription))
-	fmt.Fprintln(wr, ".SH SYNOPSIS")
+	fmt.Fprintf(wr, ".SH %s\n", p.Message(MsgManSynopsis))
 
 	usage := p.Usage
 
 	if len(usage) == 0 {
-		usage = "[OPTIONS]"
+		usage = p.Message(MsgUsageOptions)
 	}
 
 	fmt.Fprintf(wr, "\\fB%s\\fP %s\n", manQuote(p.Name), manQuote(usage))
-	fmt.Fprintln(wr, ".SH DESCRIPTION")
+	fmt.Fprintf(wr, ".SH %s\n", p.Message(MsgManDescription))
 
 	formatForMan(wr, p.LongDescription, manQuoteLines)
 	fmt.Fprintln(wr, "")
 
-	fmt.Fprintln(wr, ".SH OPTIONS")
+	fmt.Fprintf(wr, ".SH %s\n", p.Message(MsgManOptions))
 
-	writeManPageOptions(wr, p.Command.Group)
+	writeManPageOptions(wr, p, p.Command.Group)
 
 	if len(p.visibleCommands()) > 0 {
-		fmt.Fprintln(wr, ".SH COMMANDS")
+		fmt.Fprintf(wr, ".SH %s\n", p.Message(MsgManCommands))
 
-		writeManPageSubcommands(wr, "", p.Name+" "+usage, p.Command)
+		writeManPageSubcommands(wr, p, "", p.Name+" "+usage, p.Command)
 	}
 }
@@ -1,7 +1,11 @@
 package flags
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
+	"net"
+	"strings"
 	"testing"
 )
 
@@ -33,6 +37,34 @@ func (m marshalledError) MarshalFlag() (string, error) {
 	return "", newErrorf(ErrMarshal, "Failed to marshal")
 }
 
+type textMarshalled struct {
+	value string
+}
+
+func (m *textMarshalled) UnmarshalText(text []byte) error {
+	m.value = strings.ToUpper(string(text))
+	return nil
+}
+
+func (m textMarshalled) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(m.value)), nil
+}
+
+type flagValued []string
+
+func (f *flagValued) Set(value string) error {
+	*f = append(*f, strings.Split(value, ",")...)
+	return nil
+}
+
+func (f *flagValued) String() string {
+	if f == nil {
+		return ""
+	}
+
+	return strings.Join(*f, ",")
+}
+
 func TestUnmarshal(t *testing.T) {
 	var opts = struct {
 		Value marshalled `short:"v"`
@@ -117,3 +149,87 @@ func TestMarshalError(t *testing.T) {
 
 	assertError(t, err, ErrMarshal, "Failed to marshal")
 }
+
+func TestUnmarshalText(t *testing.T) {
+	var opts = struct {
+		Value  textMarshalled            `long:"value"`
+		Values []textMarshalled          `long:"values"`
+		Map    map[string]textMarshalled `long:"map"`
+		Ptr    *textMarshalled           `long:"ptr"`
+		IP     net.IP                    `long:"ip"`
+		IPs    []net.IP                  `long:"ips"`
+		Int    *big.Int                  `long:"int"`
+		Flag   flagValued                `long:"flag"`
+	}{}
+
+	ret := assertParseSuccess(t, &opts,
+		"--value=a", "--values=b", "--values=c", "--map=k:d", "--ptr=e",
+		"--ip=127.0.0.1", "--ips=::1", "--ips=10.0.0.1",
+		"--int=123456789012345678901234567890", "--flag=f,g")
+
+	assertStringArray(t, ret, []string{})
+
+	assertString(t, opts.Value.value, "A")
+
+	if len(opts.Values) != 2 || opts.Values[0].value != "B" || opts.Values[1].value != "C" {
+		t.Errorf("Expected Values to be [B C], but got %v", opts.Values)
+	}
+
+	assertString(t, opts.Map["k"].value, "D")
+
+	if opts.Ptr == nil {
+		t.Fatalf("Expected Ptr to be set")
+	}
+
+	assertString(t, opts.Ptr.value, "E")
+
+	if !opts.IP.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("Expected IP to be 127.0.0.1, but got %v", opts.IP)
+	}
+
+	if len(opts.IPs) != 2 || !opts.IPs[0].Equal(net.IPv6loopback) || !opts.IPs[1].Equal(net.IPv4(10, 0, 0, 1)) {
+		t.Errorf("Expected IPs to be [::1 10.0.0.1], but got %v", opts.IPs)
+	}
+
+	assertString(t, opts.Int.String(), "123456789012345678901234567890")
+	assertStringArray(t, opts.Flag, []string{"f", "g"})
+
+	_, err := NewParser(&opts, None).ParseArgs([]string{"--ip=abc"})
+	assertError(t, err, ErrMarshal, "invalid argument for flag `"+defaultLongOptDelimiter+"ip' (expected IP address): invalid IP address: abc")
+}
+
+func TestMarshalText(t *testing.T) {
+	var opts = struct {
+		Value textMarshalled `long:"value" description:"value"`
+		IP    net.IP         `long:"ip" description:"ip"`
+		Ptr   *big.Int       `long:"ptr" description:"ptr"`
+	}{
+		Value: textMarshalled{value: "ABC"},
+		IP:    net.IPv4(192, 168, 0, 1),
+	}
+
+	p := NewNamedParser("test", None)
+	p.AddGroup("Application Options", "", &opts)
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var help bytes.Buffer
+	p.WriteHelp(&help)
+
+	for _, expected := range []string{"value (default: abc)", "ip (default: 192.168.0.1)"} {
+		if !strings.Contains(help.String(), expected) {
+			t.Errorf("Expected help to contain %q, but got:\n%s", expected, help.String())
+		}
+	}
+
+	var ini bytes.Buffer
+	NewIniParser(p).Write(&ini, IniIncludeDefaults)
+
+	for _, expected := range []string{"Value = abc\n", "IP = 192.168.0.1\n"} {
+		if !strings.Contains(ini.String(), expected) {
+			t.Errorf("Expected ini to contain %q, but got:\n%s", expected, ini.String())
+		}
+	}
+}
@@ -0,0 +1,626 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MessageKey identifies a user-facing message of the parser, such as an
+// error message or a heading in the built-in help.
+type MessageKey string
+
+// Message keys for all the built-in messages. The comment of each key shows
+// the English format string, the arguments passed when formatting the message
+// are in the same order as the verbs of the English format string.
+const (
+	// MsgUsage is the help heading "Usage:".
+	MsgUsage MessageKey = "usage"
+
+	// MsgUsageOptions is the usage placeholder "[OPTIONS]".
+	MsgUsageOptions MessageKey = "usage-options"
+
+	// MsgUsageCommandOptions is the usage placeholder "[%s-OPTIONS]" for a
+	// command name.
+	MsgUsageCommandOptions MessageKey = "usage-command-options"
+
+	// MsgApplicationOptions is the name of the default option group,
+	// "Application Options".
+	MsgApplicationOptions MessageKey = "application-options"
+
+	// MsgHelpOptions is the name of the built-in help group, "Help Options".
+	MsgHelpOptions MessageKey = "help-options"
+
+	// MsgShowHelp is the description of the built-in help option, "Show this
+	// help message".
+	MsgShowHelp MessageKey = "show-help"
+
+	// MsgCommandOptions is the help heading "[%s command options]" for a
+	// command name.
+	MsgCommandOptions MessageKey = "command-options"
+
+	// MsgArguments is the help heading "Arguments:".
+	MsgArguments MessageKey = "arguments"
+
+	// MsgCommandArguments is the help heading "[%s command arguments]" for a
+	// command name.
+	MsgCommandArguments MessageKey = "command-arguments"
+
+	// MsgAvailableCommands is the help heading "Available commands:".
+	MsgAvailableCommands MessageKey = "available-commands"
+
+	// MsgAliases is the help annotation "(aliases: %s)" for a list of
+	// command aliases.
+	MsgAliases MessageKey = "aliases"
+
+	// MsgStructKeys is the help annotation "(keys: %s)" listing the keys of
+	// struct valued options.
+	MsgStructKeys MessageKey = "struct-keys"
+
+	// MsgDefault is the help annotation "(default: %v)" for a default value.
+	MsgDefault MessageKey = "default"
+
+	// MsgTryHelp is the hint "Try '%s' for more information." written by
+	// Parser.Exit for a help invocation.
+	MsgTryHelp MessageKey = "try-help"
+
+	// MsgManName is the man page section title "NAME".
+	MsgManName MessageKey = "man-name"
+
+	// MsgManSynopsis is the man page section title "SYNOPSIS".
+	MsgManSynopsis MessageKey = "man-synopsis"
+
+	// MsgManDescription is the man page section title "DESCRIPTION".
+	MsgManDescription MessageKey = "man-description"
+
+	// MsgManOptions is the man page section title "OPTIONS".
+	MsgManOptions MessageKey = "man-options"
+
+	// MsgManCommands is the man page section title "COMMANDS".
+	MsgManCommands MessageKey = "man-commands"
+
+	// MsgManUsage is the man page label "Usage".
+	MsgManUsage MessageKey = "man-usage"
+
+	// MsgManAliases is the man page label "Aliases".
+	MsgManAliases MessageKey = "man-aliases"
+
+	// MsgManDefault is the man page label "default".
+	MsgManDefault MessageKey = "man-default"
+
+	// MsgManRequired is the man page annotation "required".
+	MsgManRequired MessageKey = "man-required"
+
+	// MsgUnknownFlag is the error "unknown flag `%s'" for a flag name.
+	MsgUnknownFlag MessageKey = "unknown-flag"
+
+	// MsgUnknownCommand is the error "Unknown command `%s'" for a command
+	// name.
+	MsgUnknownCommand MessageKey = "unknown-command"
+
+	// MsgDidYouMean extends an error message with a suggestion, "%s, did you
+	// mean `%s'?".
+	MsgDidYouMean MessageKey = "did-you-mean"
+
+	// MsgUseCommand extends an error message with the only available
+	// command, "%s. You should use the %s command".
+	MsgUseCommand MessageKey = "use-command"
+
+	// MsgSpecifyOneCommandOf extends an error message with the available
+	// commands, "%s. Please specify one command of: %s or %s".
+	MsgSpecifyOneCommandOf MessageKey = "specify-one-command-of"
+
+	// MsgCommandRequired is the error "Please specify the %s command" for
+	// the only available command.
+	MsgCommandRequired MessageKey = "command-required"
+
+	// MsgOneCommandRequired is the error "Please specify one command of: %s
+	// or %s" for the available commands.
+	MsgOneCommandRequired MessageKey = "one-command-required"
+
+	// MsgRequiredFlag is the error "the required flag %s was not specified".
+	MsgRequiredFlag MessageKey = "required-flag"
+
+	// MsgRequiredFlags is the error "the required flags %s and %s were not
+	// specified".
+	MsgRequiredFlags MessageKey = "required-flags"
+
+	// MsgRequiredArgument is the error "the required argument %s was not
+	// provided".
+	MsgRequiredArgument MessageKey = "required-argument"
+
+	// MsgRequiredArguments is the error "the required arguments %s and %s
+	// were not provided".
+	MsgRequiredArguments MessageKey = "required-arguments"
+
+	// MsgAtLeastArgument describes a missing rest argument, "%s (at least
+	// %d argument)".
+	MsgAtLeastArgument MessageKey = "at-least-argument"
+
+	// MsgAtLeastArguments describes missing rest arguments, "%s (at least
+	// %d arguments, but got only %d)".
+	MsgAtLeastArguments MessageKey = "at-least-arguments"
+
+	// MsgAtMostArgument describes superfluous rest arguments, "%s (at most
+	// %d argument)".
+	MsgAtMostArgument MessageKey = "at-most-argument"
+
+	// MsgAtMostArguments describes superfluous rest arguments, "%s (at most
+	// %d arguments, but got %d)".
+	MsgAtMostArguments MessageKey = "at-most-arguments"
+
+	// MsgZeroArguments describes superfluous rest arguments, "%s (zero
+	// arguments)".
+	MsgZeroArguments MessageKey = "zero-arguments"
+
+	// MsgExactlyArguments describes missing array arguments, "%s (exactly
+	// %d arguments, but got %d)".
+	MsgExactlyArguments MessageKey = "exactly-arguments"
+
+	// MsgBoolArgument is the error "bool flag `%s' cannot have an argument".
+	MsgBoolArgument MessageKey = "bool-argument"
+
+	// MsgExpectedArgument is the error "expected argument for flag `%s'".
+	MsgExpectedArgument MessageKey = "expected-argument"
+
+	// MsgExpectedArgumentDoubleDash is the error "expected argument for flag
+	// `%s', but got double dash `--'".
+	MsgExpectedArgumentDoubleDash MessageKey = "expected-argument-double-dash"
+
+	// MsgExpectedArgumentOption is the error "expected argument for flag
+	// `%s', but got option `%s'".
+	MsgExpectedArgumentOption MessageKey = "expected-argument-option"
+
+	// MsgExpectedArguments is the error "expected %d arguments for flag
+	// `%s', but got %d" for options consuming multiple arguments.
+	MsgExpectedArguments MessageKey = "expected-arguments"
+
+	// MsgInvalidNargsValue is the error "invalid value `%s' for %s: %s" for
+	// a value, the name of the argument of an option consuming multiple
+	// arguments and the reason.
+	MsgInvalidNargsValue MessageKey = "invalid-nargs-value"
+
+	// MsgTooFewValues is the error "expected %d values for flag `%s', but
+	// got %d" for array options.
+	MsgTooFewValues MessageKey = "too-few-values"
+
+	// MsgTooManyValues is the error "too many values for flag `%s',
+	// expected %d" for array options.
+	MsgTooManyValues MessageKey = "too-many-values"
+
+	// MsgInvalidArgument is the error "invalid argument for flag `%s': %s".
+	MsgInvalidArgument MessageKey = "invalid-argument"
+
+	// MsgInvalidArgumentExpected is the error "invalid argument for flag
+	// `%s' (expected %s): %s".
+	MsgInvalidArgumentExpected MessageKey = "invalid-argument-expected"
+
+	// MsgInvalidChoice is the error "Invalid value `%s' for option `%s'.
+	// Allowed values are: %s".
+	MsgInvalidChoice MessageKey = "invalid-choice"
+
+	// MsgOr joins the last two items of a list, "%s or %s".
+	MsgOr MessageKey = "or"
+
+	// MsgInvalidEnumValue is the conversion error "invalid value `%s',
+	// allowed values are: %s" for a value of an Enum type which is not
+	// allowed and the list of allowed values.
+	MsgInvalidEnumValue MessageKey = "invalid-enum-value"
+
+	// MsgUnknownGroup is the ini error "could not find option group `%s'".
+	MsgUnknownGroup MessageKey = "unknown-group"
+
+	// MsgUnknownIniOption is the ini error "unknown option: %s".
+	MsgUnknownIniOption MessageKey = "unknown-ini-option"
+
+	// MsgFlagRedefined is the error "flag `%s' is already defined in the
+	// flag set" when exporting an option to a flag.FlagSet.
+	MsgFlagRedefined MessageKey = "flag-redefined"
+
+	// MsgTypeIP is the expected type of net.IP options, "IP address".
+	MsgTypeIP MessageKey = "type-ip"
+
+	// MsgTypeIPNet is the expected type of net.IPNet options, "CIDR
+	// network".
+	MsgTypeIPNet MessageKey = "type-ipnet"
+
+	// MsgTypeAddrPort is the expected type of netip.AddrPort options, "IP
+	// address and port".
+	MsgTypeAddrPort MessageKey = "type-addrport"
+
+	// MsgTypeURL is the expected type of url.URL options, "URL".
+	MsgTypeURL MessageKey = "type-url"
+
+	// MsgTypeRegexp is the expected type of regexp.Regexp options, "regular
+	// expression".
+	MsgTypeRegexp MessageKey = "type-regexp"
+
+	// MsgTypeTime is the expected type of time.Time options, "time in the
+	// format %s" for a layout.
+	MsgTypeTime MessageKey = "type-time"
+
+	// MsgTypeLocation is the expected type of time.Location options, "time
+	// zone".
+	MsgTypeLocation MessageKey = "type-location"
+
+	// MsgInvalidIP is the conversion error "invalid IP address: %s" for a
+	// value.
+	MsgInvalidIP MessageKey = "invalid-ip"
+
+	// MsgInvalidByteSize is the conversion error "invalid byte size `%s'"
+	// for a value.
+	MsgInvalidByteSize MessageKey = "invalid-byte-size"
+
+	// MsgByteSizeOutOfRange is the conversion error "byte size `%s' is out
+	// of range" for a value.
+	MsgByteSizeOutOfRange MessageKey = "byte-size-out-of-range"
+
+	// MsgUnterminatedQuote is the conversion error "unterminated quote in
+	// `%s'" for a delimited value.
+	MsgUnterminatedQuote MessageKey = "unterminated-quote"
+
+	// MsgTypeStruct is the expected type of struct valued options, "comma
+	// separated key=value pairs".
+	MsgTypeStruct MessageKey = "type-struct"
+
+	// MsgUnknownStructKey is the conversion error "unknown key `%s',
+	// expected one of: %s" for a key of a struct valued option and the
+	// list of its keys.
+	MsgUnknownStructKey MessageKey = "unknown-struct-key"
+
+	// MsgExpectedStructValue is the conversion error "expected value for
+	// key `%s'" for a key of a struct valued option.
+	MsgExpectedStructValue MessageKey = "expected-struct-value"
+
+	// MsgInvalidStructChoice is the conversion error "invalid value `%s'
+	// for key `%s', allowed values are: %s" for a value, a key of a struct
+	// valued option and the list of allowed values.
+	MsgInvalidStructChoice MessageKey = "invalid-struct-choice"
+
+	// MsgInvalidStructValue is the conversion error "invalid value `%s' for
+	// key `%s': %s" for a value, a key of a struct valued option and the
+	// reason.
+	MsgInvalidStructValue MessageKey = "invalid-struct-value"
+
+	// MsgMissingStructKey is the conversion error "missing required key
+	// `%s'" for a key of a struct valued option.
+	MsgMissingStructKey MessageKey = "missing-struct-key"
+
+	// MsgInvalidCommandOptions is the validation error "invalid options for
+	// command `%s': %s" for a command name and the error returned by its
+	// Validate method.
+	MsgInvalidCommandOptions MessageKey = "invalid-command-options"
+
+	// MsgInvalidGroupOptions is the validation error "invalid options in
+	// group `%s': %s" for a group name and the error returned by its
+	// Validate method.
+	MsgInvalidGroupOptions MessageKey = "invalid-group-options"
+
+	// MsgDefaultProvider is the error "could not determine the default
+	// value of flag `%s': %s" for an option and the error returned by the
+	// DefaultProvider of its group.
+	MsgDefaultProvider MessageKey = "default-provider"
+
+	// MsgInvalidDefault is the error "invalid default value for flag `%s':
+	// %s" for an option and the reason.
+	MsgInvalidDefault MessageKey = "invalid-default"
+
+	// MsgUnterminatedVariable is the error "unterminated variable reference
+	// in `%s'" for a value.
+	MsgUnterminatedVariable MessageKey = "unterminated-variable"
+
+	// MsgUnknownVariable is the error "unknown variable `%s'" for a
+	// reference to an option which does not exist.
+	MsgUnknownVariable MessageKey = "unknown-variable"
+
+	// MsgCyclicVariable is the error "cyclic reference to variable `%s'".
+	MsgCyclicVariable MessageKey = "cyclic-variable"
+
+	// MsgUnknownEnv is the error "unknown environment variable `%s'" for
+	// the name of an environment variable.
+	MsgUnknownEnv MessageKey = "unknown-env"
+
+	// MsgMalformedDotenv is the dotenv error "malformed NAME=value (%s)"
+	// for a line.
+	MsgMalformedDotenv MessageKey = "malformed-dotenv"
+
+	// MsgInvalidDotenvName is the dotenv error "invalid variable name
+	// `%s'".
+	MsgInvalidDotenvName MessageKey = "invalid-dotenv-name"
+
+	// MsgUnterminatedDotenvValue is the dotenv error "unterminated quoted
+	// value for `%s'" for a variable name.
+	MsgUnterminatedDotenvValue MessageKey = "unterminated-dotenv-value"
+
+	// MsgDotenvTrailingCharacters is the dotenv error "unexpected
+	// characters after quoted value for `%s'" for a variable name.
+	MsgDotenvTrailingCharacters MessageKey = "dotenv-trailing-characters"
+
+	// MsgWarning is the warning "warning: %s" for a message.
+	MsgWarning MessageKey = "warning"
+
+	// MsgFromFile is the description "%s (read from a file)" of the option
+	// reading the value of an option with the given description from a
+	// file.
+	MsgFromFile MessageKey = "from-file"
+
+	// MsgReadFile is the error "could not read the value of flag `%s': %s"
+	// for an option and the error reading the file.
+	MsgReadFile MessageKey = "read-file"
+
+	// MsgInvalidSecretArgument is the error "invalid argument for flag
+	// `%s'" for a secret option, whose value is not shown.
+	MsgInvalidSecretArgument MessageKey = "invalid-secret-argument"
+
+	// MsgErrUnknown is the name of the ErrUnknown error type, "unknown".
+	MsgErrUnknown MessageKey = "err-unknown"
+
+	// MsgErrExpectedArgument is the name of the ErrExpectedArgument error
+	// type, "expected argument".
+	MsgErrExpectedArgument MessageKey = "err-expected-argument"
+
+	// MsgErrUnknownFlag is the name of the ErrUnknownFlag error type,
+	// "unknown flag".
+	MsgErrUnknownFlag MessageKey = "err-unknown-flag"
+
+	// MsgErrUnknownGroup is the name of the ErrUnknownGroup error type,
+	// "unknown group".
+	MsgErrUnknownGroup MessageKey = "err-unknown-group"
+
+	// MsgErrMarshal is the name of the ErrMarshal error type, "marshal".
+	MsgErrMarshal MessageKey = "err-marshal"
+
+	// MsgErrHelp is the name of the ErrHelp error type, "help".
+	MsgErrHelp MessageKey = "err-help"
+
+	// MsgErrNoArgumentForBool is the name of the ErrNoArgumentForBool error
+	// type, "no argument for bool".
+	MsgErrNoArgumentForBool MessageKey = "err-no-argument-for-bool"
+
+	// MsgErrRequired is the name of the ErrRequired error type, "required".
+	MsgErrRequired MessageKey = "err-required"
+
+	// MsgErrShortNameTooLong is the name of the ErrShortNameTooLong error
+	// type, "short name too long".
+	MsgErrShortNameTooLong MessageKey = "err-short-name-too-long"
+
+	// MsgErrDuplicatedFlag is the name of the ErrDuplicatedFlag error type,
+	// "duplicated flag".
+	MsgErrDuplicatedFlag MessageKey = "err-duplicated-flag"
+
+	// MsgErrTag is the name of the ErrTag error type, "tag".
+	MsgErrTag MessageKey = "err-tag"
+
+	// MsgErrCommandRequired is the name of the ErrCommandRequired error
+	// type, "command required".
+	MsgErrCommandRequired MessageKey = "err-command-required"
+
+	// MsgErrUnknownCommand is the name of the ErrUnknownCommand error type,
+	// "unknown command".
+	MsgErrUnknownCommand MessageKey = "err-unknown-command"
+
+	// MsgErrInvalidChoice is the name of the ErrInvalidChoice error type,
+	// "invalid choice".
+	MsgErrInvalidChoice MessageKey = "err-invalid-choice"
+
+	// MsgErrInvalidTag is the name of the ErrInvalidTag error type, "invalid
+	// tag".
+	MsgErrInvalidTag MessageKey = "err-invalid-tag"
+
+	// MsgErrValidation is the name of the ErrValidation error type,
+	// "validation".
+	MsgErrValidation MessageKey = "err-validation"
+
+	// MsgErrUnknownEnv is the name of the ErrUnknownEnv error type,
+	// "unknown environment variable".
+	MsgErrUnknownEnv MessageKey = "err-unknown-env"
+
+	// MsgErrUnrecognized is the name of an unrecognized error type,
+	// "unrecognized error type".
+	MsgErrUnrecognized MessageKey = "err-unrecognized"
+)
+
+// MessageCatalog is the interface implemented by message catalogs providing
+// translations of the user-facing messages of the parser. A catalog can be
+// set on the parser using the Messages field.
+type MessageCatalog interface {
+	// Message formats the message identified by key with the given
+	// arguments. If the catalog has no message for key, it should return
+	// false, in which case the English message is used instead.
+	Message(key MessageKey, args ...interface{}) (string, bool)
+}
+
+// MessageMap is a simple MessageCatalog mapping message keys to fmt format
+// strings.
+type MessageMap map[MessageKey]string
+
+// Message formats the format string stored for key with the given arguments
+// using fmt.Sprintf.
+func (m MessageMap) Message(key MessageKey, args ...interface{}) (string, bool) {
+	format, ok := m[key]
+
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprintf(format, args...), true
+}
+
+// EnglishMessages contains the default English messages of the parser. It
+// can be used as a reference for the format and arguments of each message
+// when writing a translation.
+var EnglishMessages = MessageMap{
+	MsgUsage:               "Usage:",
+	MsgUsageOptions:        "[OPTIONS]",
+	MsgUsageCommandOptions: "[%s-OPTIONS]",
+	MsgApplicationOptions:  "Application Options",
+	MsgHelpOptions:         "Help Options",
+	MsgShowHelp:            "Show this help message",
+	MsgCommandOptions:      "[%s command options]",
+	MsgArguments:           "Arguments:",
+	MsgCommandArguments:    "[%s command arguments]",
+	MsgAvailableCommands:   "Available commands:",
+	MsgAliases:             "(aliases: %s)",
+	MsgStructKeys:          "(keys: %s)",
+	MsgDefault:             "(default: %v)",
+	MsgTryHelp:             "Try '%s' for more information.",
+
+	MsgManName:        "NAME",
+	MsgManSynopsis:    "SYNOPSIS",
+	MsgManDescription: "DESCRIPTION",
+	MsgManOptions:     "OPTIONS",
+	MsgManCommands:    "COMMANDS",
+	MsgManUsage:       "Usage",
+	MsgManAliases:     "Aliases",
+	MsgManDefault:     "default",
+	MsgManRequired:    "required",
+
+	MsgUnknownFlag:                "unknown flag `%s'",
+	MsgUnknownCommand:             "Unknown command `%s'",
+	MsgDidYouMean:                 "%s, did you mean `%s'?",
+	MsgUseCommand:                 "%s. You should use the %s command",
+	MsgSpecifyOneCommandOf:        "%s. Please specify one command of: %s or %s",
+	MsgCommandRequired:            "Please specify the %s command",
+	MsgOneCommandRequired:         "Please specify one command of: %s or %s",
+	MsgRequiredFlag:               "the required flag %s was not specified",
+	MsgRequiredFlags:              "the required flags %s and %s were not specified",
+	MsgRequiredArgument:           "the required argument %s was not provided",
+	MsgRequiredArguments:          "the required arguments %s and %s were not provided",
+	MsgAtLeastArgument:            "%s (at least %d argument)",
+	MsgAtLeastArguments:           "%s (at least %d arguments, but got only %d)",
+	MsgAtMostArgument:             "%s (at most %d argument)",
+	MsgAtMostArguments:            "%s (at most %d arguments, but got %d)",
+	MsgZeroArguments:              "%s (zero arguments)",
+	MsgExactlyArguments:           "%s (exactly %d arguments, but got %d)",
+	MsgBoolArgument:               "bool flag `%s' cannot have an argument",
+	MsgExpectedArgument:           "expected argument for flag `%s'",
+	MsgExpectedArgumentDoubleDash: "expected argument for flag `%s', but got double dash `--'",
+	MsgExpectedArgumentOption:     "expected argument for flag `%s', but got option `%s'",
+	MsgExpectedArguments:          "expected %d arguments for flag `%s', but got %d",
+	MsgInvalidNargsValue:          "invalid value `%s' for %s: %s",
+	MsgTooFewValues:               "expected %d values for flag `%s', but got %d",
+	MsgTooManyValues:              "too many values for flag `%s', expected %d",
+	MsgInvalidArgument:            "invalid argument for flag `%s': %s",
+	MsgInvalidArgumentExpected:    "invalid argument for flag `%s' (expected %s): %s",
+	MsgInvalidChoice:              "Invalid value `%s' for option `%s'. Allowed values are: %s",
+	MsgOr:                         "%s or %s",
+	MsgInvalidEnumValue:           "invalid value `%s', allowed values are: %s",
+	MsgUnknownGroup:               "could not find option group `%s'",
+	MsgUnknownIniOption:           "unknown option: %s",
+	MsgFlagRedefined:              "flag `%s' is already defined in the flag set",
+	MsgTypeIP:                     "IP address",
+	MsgTypeIPNet:                  "CIDR network",
+	MsgTypeAddrPort:               "IP address and port",
+	MsgTypeURL:                    "URL",
+	MsgTypeRegexp:                 "regular expression",
+	MsgTypeTime:                   "time in the format %s",
+	MsgTypeLocation:               "time zone",
+	MsgInvalidIP:                  "invalid IP address: %s",
+	MsgInvalidByteSize:            "invalid byte size `%s'",
+	MsgByteSizeOutOfRange:         "byte size `%s' is out of range",
+	MsgUnterminatedQuote:          "unterminated quote in `%s'",
+	MsgTypeStruct:                 "comma separated key=value pairs",
+	MsgUnknownStructKey:           "unknown key `%s', expected one of: %s",
+	MsgExpectedStructValue:        "expected value for key `%s'",
+	MsgInvalidStructChoice:        "invalid value `%s' for key `%s', allowed values are: %s",
+	MsgInvalidStructValue:         "invalid value `%s' for key `%s': %s",
+	MsgMissingStructKey:           "missing required key `%s'",
+	MsgInvalidCommandOptions:      "invalid options for command `%s': %s",
+	MsgInvalidGroupOptions:        "invalid options in group `%s': %s",
+	MsgDefaultProvider:            "could not determine the default value of flag `%s': %s",
+	MsgInvalidDefault:             "invalid default value for flag `%s': %s",
+	MsgUnterminatedVariable:       "unterminated variable reference in `%s'",
+	MsgUnknownVariable:            "unknown variable `%s'",
+	MsgCyclicVariable:             "cyclic reference to variable `%s'",
+	MsgUnknownEnv:                 "unknown environment variable `%s'",
+	MsgMalformedDotenv:            "malformed NAME=value (%s)",
+	MsgInvalidDotenvName:          "invalid variable name `%s'",
+	MsgUnterminatedDotenvValue:    "unterminated quoted value for `%s'",
+	MsgDotenvTrailingCharacters:   "unexpected characters after quoted value for `%s'",
+	MsgWarning:                    "warning: %s",
+	MsgFromFile:                   "%s (read from a file)",
+	MsgReadFile:                   "could not read the value of flag `%s': %s",
+	MsgInvalidSecretArgument:      "invalid argument for flag `%s'",
+
+	MsgErrUnknown:           "unknown",
+	MsgErrExpectedArgument:  "expected argument",
+	MsgErrUnknownFlag:       "unknown flag",
+	MsgErrUnknownGroup:      "unknown group",
+	MsgErrMarshal:           "marshal",
+	MsgErrHelp:              "help",
+	MsgErrNoArgumentForBool: "no argument for bool",
+	MsgErrRequired:          "required",
+	MsgErrShortNameTooLong:  "short name too long",
+	MsgErrDuplicatedFlag:    "duplicated flag",
+	MsgErrTag:               "tag",
+	MsgErrCommandRequired:   "command required",
+	MsgErrUnknownCommand:    "unknown command",
+	MsgErrInvalidChoice:     "invalid choice",
+	MsgErrInvalidTag:        "invalid tag",
+	MsgErrValidation:        "validation",
+	MsgErrUnknownEnv:        "unknown environment variable",
+	MsgErrUnrecognized:      "unrecognized error type",
+}
+
+// Message formats the message identified by key with the given arguments,
+// using the parser's message catalog. Messages missing from the catalog, or
+// all messages when no catalog has been set, are formatted in English.
+func (p *Parser) Message(key MessageKey, args ...interface{}) string {
+	if p != nil && p.Messages != nil {
+		if msg, ok := p.Messages.Message(key, args...); ok {
+			return msg
+		}
+	}
+
+	msg, _ := EnglishMessages.Message(key, args...)
+	return msg
+}
+
+// ErrorTypeString returns the name of the error type using the parser's
+// message catalog (see ErrorType.String).
+func (p *Parser) ErrorTypeString(e ErrorType) string {
+	return p.Message(e.messageKey())
+}
+
+func (p *Parser) newError(tp ErrorType, key MessageKey, args ...interface{}) *Error {
+	return newError(tp, p.Message(key, args...))
+}
+
+// messageError is an error returned where no parser is available, such as
+// when converting values. Its message is looked up in the message catalog
+// of the parser reporting the error.
+type messageError struct {
+	message func(p *Parser) string
+}
+
+func newMessageError(key MessageKey, args ...interface{}) error {
+	return &messageError{
+		message: func(p *Parser) string {
+			return p.Message(key, args...)
+		},
+	}
+}
+
+// Error returns the English message of the error.
+func (e *messageError) Error() string {
+	return e.message(nil)
+}
+
+// errorMessage returns the message of err, using the parser's message
+// catalog if err is a messageError.
+func (p *Parser) errorMessage(err error) string {
+	if merr, ok := err.(*messageError); ok {
+		return merr.message(p)
+	}
+
+	return err.Error()
+}
+
+// joinList joins the items of a list with commas, the last two items being
+// joined using MsgOr.
+func (p *Parser) joinList(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+
+	return p.Message(MsgOr, strings.Join(items[:len(items)-1], ", "), items[len(items)-1])
+}
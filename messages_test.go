@@ -0,0 +1,355 @@
+package flags
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMessagesFallback(t *testing.T) {
+	p := NewNamedParser("test", Default)
+
+	assertString(t, p.Message(MsgUnknownFlag, "x"), "unknown flag `x'")
+
+	p.Messages = MessageMap{
+		MsgUnknownFlag: "unbekannte Option `%s'",
+	}
+
+	assertString(t, p.Message(MsgUnknownFlag, "x"), "unbekannte Option `x'")
+	assertString(t, p.Message(MsgUsage), "Usage:")
+}
+
+// messagesOutput returns the output in which a localized message is
+// expected, e.g. the message of the error returned by parsing arguments.
+type messagesOutput func(p *Parser) string
+
+func messagesParseArgs(args ...string) messagesOutput {
+	return func(p *Parser) string {
+		_, err := p.ParseArgs(args)
+
+		if err == nil {
+			return ""
+		}
+
+		return err.Error()
+	}
+}
+
+func messagesParseIni(ini string) messagesOutput {
+	return func(p *Parser) string {
+		err := NewIniParser(p).Parse(strings.NewReader(ini))
+
+		if err == nil {
+			return ""
+		}
+
+		return err.Error()
+	}
+}
+
+func messagesLoadDotenv(dotenv string) messagesOutput {
+	return func(p *Parser) string {
+		err := p.LoadDotenv(strings.NewReader(dotenv))
+
+		if err == nil {
+			return ""
+		}
+
+		return err.Error()
+	}
+}
+
+func messagesHelp(p *Parser) string {
+	var b bytes.Buffer
+	p.WriteHelp(&b)
+
+	return b.String()
+}
+
+func messagesExportFlagSet(p *Parser) string {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("value", "", "an existing flag")
+
+	if err := p.ExportFlagSet(fs); err != nil {
+		return err.Error()
+	}
+
+	return ""
+}
+
+type messagesStruct struct {
+	Host    string `required:"true"`
+	Mode    string `choice:"fast" choice:"slow"`
+	Size    ByteSize
+	Verbose bool
+}
+
+func TestMessagesKeys(t *testing.T) {
+	// Every message key declared in messages.go has an English message
+	// and can be translated
+	file, err := parser.ParseFile(token.NewFileSet(), "messages.go", nil, 0)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var keys []MessageKey
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			vspec := spec.(*ast.ValueSpec)
+
+			if ident, ok := vspec.Type.(*ast.Ident); !ok || ident.Name != "MessageKey" {
+				continue
+			}
+
+			for _, value := range vspec.Values {
+				key, _ := strconv.Unquote(value.(*ast.BasicLit).Value)
+				keys = append(keys, MessageKey(key))
+			}
+		}
+	}
+
+	if len(keys) != len(EnglishMessages) {
+		t.Errorf("Expected %d English messages, but got %d", len(keys), len(EnglishMessages))
+	}
+
+	p := NewNamedParser("test", None)
+
+	for _, key := range keys {
+		if EnglishMessages[key] == "" {
+			t.Errorf("Expected an English message for %s", key)
+		}
+
+		p.Messages = MessageMap{key: "translated " + string(key)}
+		assertString(t, p.Message(key), "translated "+string(key))
+	}
+}
+
+func TestMessagesErrors(t *testing.T) {
+	tests := []struct {
+		messages MessageMap
+		data     interface{}
+		output   messagesOutput
+		expected string
+	}{
+		{
+			MessageMap{MsgRequiredFlag: "die erforderliche Option %s fehlt"},
+			&struct {
+				Value string `long:"value" required:"true"`
+			}{},
+			messagesParseArgs(),
+			"die erforderliche Option `" + defaultLongOptDelimiter + "value' fehlt",
+		},
+		{
+			MessageMap{MsgInvalidChoice: "Ungültiger Wert `%s' für Option `%s'. Erlaubt sind: %s", MsgOr: "%s oder %s"},
+			&struct {
+				Value string `long:"value" choice:"a" choice:"b"`
+			}{},
+			messagesParseArgs("--value=c"),
+			"Ungültiger Wert `c' für Option `" + defaultLongOptDelimiter + "value'. Erlaubt sind: a oder b",
+		},
+		{
+			MessageMap{MsgInvalidIP: "ungültige IP-Adresse: %s"},
+			&struct {
+				IP net.IP `long:"ip"`
+			}{},
+			messagesParseArgs("--ip=abc"),
+			"invalid argument for flag `" + defaultLongOptDelimiter + "ip' (expected IP address): ungültige IP-Adresse: abc",
+		},
+		{
+			MessageMap{MsgInvalidIP: "ungültige IP-Adresse: %s"},
+			&struct {
+				IP net.IP `long:"ip"`
+			}{},
+			messagesParseIni("[Application Options]\nIP = abc\n"),
+			":2: ungültige IP-Adresse: abc",
+		},
+		{
+			MessageMap{MsgInvalidByteSize: "ungültige Größe `%s'"},
+			&struct {
+				Cache ByteSize `long:"cache"`
+			}{},
+			messagesParseArgs("--cache=lots"),
+			"invalid argument for flag `" + defaultLongOptDelimiter + "cache' (expected flags.ByteSize): ungültige Größe `lots'",
+		},
+		{
+			MessageMap{MsgByteSizeOutOfRange: "Größe `%s' außerhalb des Bereichs"},
+			&struct {
+				Cache ByteSize `long:"cache"`
+			}{},
+			messagesParseArgs("--cache=16EiB"),
+			"invalid argument for flag `" + defaultLongOptDelimiter + "cache' (expected flags.ByteSize): Größe `16EiB' außerhalb des Bereichs",
+		},
+		{
+			MessageMap{MsgUnterminatedQuote: "nicht beendetes Anführungszeichen in `%s'"},
+			&struct {
+				Tags []string `long:"tags" delim:","`
+			}{},
+			messagesParseArgs(`--tags=a,"b`),
+			"invalid argument for flag `" + defaultLongOptDelimiter + "tags' (expected []string): nicht beendetes Anführungszeichen in `a,\"b'",
+		},
+		{
+			MessageMap{MsgUnterminatedQuote: "nicht beendetes Anführungszeichen in `%s'"},
+			&struct {
+				S messagesStruct `long:"s"`
+			}{},
+			messagesParseArgs(`--s=host="a`),
+			"invalid argument for flag `" + defaultLongOptDelimiter + "s' (expected comma separated key=value pairs): nicht beendetes Anführungszeichen in `host=\"a'",
+		},
+		{
+			MessageMap{MsgUnknownStructKey: "unbekannter Schlüssel `%s', erwartet: %s"},
+			&struct {
+				S messagesStruct `long:"s"`
+			}{},
+			messagesParseArgs("--s=host=a,user=b"),
+			"invalid argument for flag `" + defaultLongOptDelimiter + "s' (expected comma separated key=value pairs): unbekannter Schlüssel `user', erwartet: host, mode, size, verbose",
+		},
+		{
+			MessageMap{MsgExpectedStructValue: "Wert für Schlüssel `%s' erwartet"},
+			&struct {
+				S messagesStruct `long:"s"`
+			}{},
+			messagesParseArgs("--s=host"),
+			"invalid argument for flag `" + defaultLongOptDelimiter + "s' (expected comma separated key=value pairs): Wert für Schlüssel `host' erwartet",
+		},
+		{
+			MessageMap{MsgInvalidStructChoice: "ungültiger Wert `%s' für Schlüssel `%s', erlaubt sind: %s"},
+			&struct {
+				S messagesStruct `long:"s"`
+			}{},
+			messagesParseArgs("--s=host=a,mode=medium"),
+			"invalid argument for flag `" + defaultLongOptDelimiter + "s' (expected comma separated key=value pairs): ungültiger Wert `medium' für Schlüssel `mode', erlaubt sind: fast, slow",
+		},
+		{
+			MessageMap{MsgInvalidStructValue: "ungültiger Wert `%s' für Schlüssel `%s': %s", MsgInvalidByteSize: "ungültige Größe `%s'"},
+			&struct {
+				S messagesStruct `long:"s"`
+			}{},
+			messagesParseArgs("--s=host=a,size=lots"),
+			"invalid argument for flag `" + defaultLongOptDelimiter + "s' (expected comma separated key=value pairs): ungültiger Wert `lots' für Schlüssel `size': ungültige Größe `lots'",
+		},
+		{
+			MessageMap{MsgMissingStructKey: "erforderlicher Schlüssel `%s' fehlt"},
+			&struct {
+				S messagesStruct `long:"s"`
+			}{},
+			messagesParseArgs("--s=size=1k"),
+			"invalid argument for flag `" + defaultLongOptDelimiter + "s' (expected comma separated key=value pairs): erforderlicher Schlüssel `host' fehlt",
+		},
+		{
+			MessageMap{MsgInvalidNargsValue: "ungültiger Wert `%s' für %s: %s"},
+			&struct {
+				Resize []struct {
+					Width  int
+					Height int
+				} `long:"resize" nargs:"2" value-name:"WIDTH" value-name:"HEIGHT"`
+			}{},
+			messagesParseArgs("--resize", "640", "tall"),
+			"invalid argument for flag `" + defaultLongOptDelimiter + "resize': ungültiger Wert `tall' für HEIGHT: ",
+		},
+		{
+			MessageMap{MsgInvalidEnumValue: "ungültiger Wert `%s', erlaubt sind: %s", MsgOr: "%s oder %s"},
+			&struct {
+				Levels map[string]testLevel `long:"levels"`
+			}{},
+			messagesParseArgs("--levels=a:warn"),
+			"invalid argument for flag `" + defaultLongOptDelimiter + "levels' (expected map[string]flags.testLevel): ungültiger Wert `warn', erlaubt sind: debug, info oder error",
+		},
+		{
+			MessageMap{MsgFlagRedefined: "Option `%s' ist bereits definiert"},
+			&struct {
+				Value string `long:"value"`
+			}{},
+			messagesExportFlagSet,
+			"Option `value' ist bereits definiert",
+		},
+		{
+			MessageMap{MsgFromFile: "%s (aus Datei)"},
+			&struct {
+				Password string `long:"password" file:"password-file" description:"Database password"`
+			}{},
+			messagesHelp,
+			"Database password (aus Datei)",
+		},
+		{
+			MessageMap{MsgMalformedDotenv: "fehlerhaftes NAME=Wert (%s)"},
+			&struct{}{},
+			messagesLoadDotenv("A"),
+			":1: fehlerhaftes NAME=Wert (A)",
+		},
+		{
+			MessageMap{MsgInvalidDotenvName: "ungültiger Variablenname `%s'"},
+			&struct{}{},
+			messagesLoadDotenv("1A=b"),
+			":1: ungültiger Variablenname `1A'",
+		},
+		{
+			MessageMap{MsgUnterminatedDotenvValue: "nicht beendeter Wert für `%s'"},
+			&struct{}{},
+			messagesLoadDotenv("A=\"b"),
+			":1: nicht beendeter Wert für `A'",
+		},
+		{
+			MessageMap{MsgDotenvTrailingCharacters: "unerwartete Zeichen nach dem Wert für `%s'"},
+			&struct{}{},
+			messagesLoadDotenv("A=\"b\" c"),
+			":1: unerwartete Zeichen nach dem Wert für `A'",
+		},
+	}
+
+	for _, test := range tests {
+		p := NewNamedParser("test", None)
+		p.AddGroup("Application Options", "", test.data)
+		p.Messages = test.messages
+
+		if output := test.output(p); !strings.Contains(output, test.expected) {
+			t.Errorf("Expected output to contain %q, but got:\n%s", test.expected, output)
+		}
+	}
+}
+
+func TestMessagesHelp(t *testing.T) {
+	var opts struct {
+		Value string `long:"value" description:"A value" default:"x"`
+	}
+
+	p := NewParser(&opts, HelpFlag)
+	p.Name = "test"
+
+	p.Messages = MessageMap{
+		MsgUsage:              "Aufruf:",
+		MsgUsageOptions:       "[OPTIONEN]",
+		MsgApplicationOptions: "Anwendungsoptionen",
+		MsgHelpOptions:        "Hilfeoptionen",
+		MsgShowHelp:           "Diese Hilfe anzeigen",
+		MsgDefault:            "(Standard: %v)",
+	}
+
+	_, err := p.ParseArgs([]string{"--help"})
+
+	if !WroteHelp(err) {
+		t.Fatalf("Expected help error, but got %v", err)
+	}
+
+	var b bytes.Buffer
+	p.WriteHelp(&b)
+
+	for _, expected := range []string{"Aufruf:", "test [OPTIONEN]", "Anwendungsoptionen:", "Hilfeoptionen:", "Diese Hilfe anzeigen", "(Standard: x)"} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("Expected help to contain %q, but got:\n%s", expected, b.String())
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package flags
+
+import (
+	"strings"
+	"unicode"
+)
+
+// normalizeName normalizes a long option name by splitting it into words at
+// underscores, dashes and camelCase boundaries, and joining the lower case
+// words with dashes. For example, dry_run, dryRun and DryRun are all
+// normalized to dry-run.
+func normalizeName(name string) string {
+	var ret strings.Builder
+
+	runes := []rune(name)
+	separate := false
+
+	for i, r := range runes {
+		if r == '_' || r == '-' {
+			separate = ret.Len() != 0
+			continue
+		}
+
+		// Split before an upper case letter following a lower case
+		// letter or digit, or starting a new word after an acronym
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				separate = true
+			}
+		}
+
+		if separate {
+			ret.WriteRune('-')
+			separate = false
+		}
+
+		ret.WriteRune(unicode.ToLower(r))
+	}
+
+	return ret.String()
+}
+
+// longNameKey returns the key by which the given long option name is looked
+// up, according to the IgnoreCase and NormalizeNames options.
+func (p *Parser) longNameKey(name string) string {
+	if p == nil {
+		return name
+	}
+
+	if (p.Options & NormalizeNames) != None {
+		name = normalizeName(name)
+	}
+
+	if (p.Options & IgnoreCase) != None {
+		name = strings.ToLower(name)
+	}
+
+	return name
+}
+
+// matchChoice returns the choice matching value, comparing case insensitively
+// when the IgnoreChoiceCase option is set.
+func (p *Parser) matchChoice(choices []string, value string) (string, bool) {
+	for _, choice := range choices {
+		if choice == value {
+			return choice, true
+		}
+	}
+
+	if p == nil || (p.Options&IgnoreChoiceCase) == None {
+		return "", false
+	}
+
+	for _, choice := range choices {
+		if strings.EqualFold(choice, value) {
+			return choice, true
+		}
+	}
+
+	return "", false
+}
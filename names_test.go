@@ -0,0 +1,109 @@
+package flags
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := map[string]string{
+		"dry-run":    "dry-run",
+		"dry_run":    "dry-run",
+		"dryRun":     "dry-run",
+		"DryRun":     "dry-run",
+		"JSONOutput": "json-output",
+		"output2Dir": "output2-dir",
+		"dry__run":   "dry-run",
+		"db.maxConn": "db.max-conn",
+	}
+
+	for name, expected := range tests {
+		assertString(t, normalizeName(name), expected)
+	}
+}
+
+func TestIgnoreCase(t *testing.T) {
+	var opts = struct {
+		Format string `long:"format"`
+		Value  string `short:"v"`
+	}{}
+
+	p := NewParser(&opts, IgnoreCase)
+
+	if _, err := p.ParseArgs([]string{"--Format=json"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.Format, "json")
+
+	_, err := p.ParseArgs([]string{"-V", "x"})
+	assertError(t, err, ErrUnknownFlag, "unknown flag `V'")
+}
+
+func TestNormalizeNames(t *testing.T) {
+	var opts = struct {
+		DryRun  bool `long:"dry-run"`
+		MaxConn int  `long:"maxConn"`
+	}{}
+
+	p := NewParser(&opts, NormalizeNames)
+
+	for _, args := range [][]string{{"--dry_run", "--max-conn", "1"}, {"--dryRun", "--max_conn=2"}, {"--dry-run", "--maxConn", "3"}} {
+		opts.DryRun = false
+
+		if _, err := p.ParseArgs(args); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !opts.DryRun {
+			t.Errorf("Expected DryRun to be set for %v", args)
+		}
+	}
+
+	if opts.MaxConn != 3 {
+		t.Errorf("Expected MaxConn to be 3, but got %d", opts.MaxConn)
+	}
+
+	c := &completion{parser: p}
+	ret := c.complete([]string{"--max_"})
+
+	if !reflect.DeepEqual(ret, []Completion{{Item: defaultLongOptDelimiter + "maxConn"}}) {
+		t.Errorf("Unexpected completions %v", ret)
+	}
+}
+
+func TestNormalizeNamesDuplicate(t *testing.T) {
+	var opts = struct {
+		DryRun  bool `long:"dry-run"`
+		DryRun2 bool `long:"dry_run"`
+	}{}
+
+	p := NewNamedParser("test", NormalizeNames)
+	_, err := p.AddGroup("Application Options", "", &opts)
+
+	assertError(t, err, ErrDuplicatedFlag, "option `"+defaultLongOptDelimiter+"dry_run' uses the same long name as option `"+defaultLongOptDelimiter+"dry-run'")
+
+	// Without normalization the names are distinct
+	p = NewNamedParser("test", None)
+
+	if _, err := p.AddGroup("Application Options", "", &opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestIgnoreChoiceCase(t *testing.T) {
+	var opts = struct {
+		Format string `long:"format" choice:"json" choice:"yaml"`
+	}{}
+
+	p := NewParser(&opts, IgnoreChoiceCase)
+
+	if _, err := p.ParseArgs([]string{"--format", "JSON"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.Format, "json")
+
+	assertParseFail(t, ErrInvalidChoice, "Invalid value `JSON' for option `"+defaultLongOptDelimiter+"format'. Allowed values are: json or yaml",
+		&opts, "--format", "JSON")
+}
@@ -0,0 +1,158 @@
+package flags
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type nargsRename struct {
+	Old string
+	New string
+}
+
+func TestNargs(t *testing.T) {
+	var opts = struct {
+		Verbose bool          `short:"v" long:"verbose"`
+		Point   []int         `long:"point" nargs:"2"`
+		Rename  []nargsRename `long:"rename" nargs:"2"`
+		Files   []string      `long:"files" nargs:"1-3"`
+		Size    [2]int        `long:"size" nargs:"2"`
+	}{}
+
+	ret := assertParseSuccess(t, &opts,
+		"--point", "1", "-2",
+		"--rename", "a", "b",
+		"--rename=c", "d",
+		"--files", "x", "y", "-v",
+		"--size", "640", "480",
+		"rest")
+
+	assertStringArray(t, ret, []string{"rest"})
+
+	if !reflect.DeepEqual(opts.Point, []int{1, -2}) {
+		t.Errorf("Unexpected point %v", opts.Point)
+	}
+
+	if !reflect.DeepEqual(opts.Rename, []nargsRename{{"a", "b"}, {"c", "d"}}) {
+		t.Errorf("Unexpected renames %v", opts.Rename)
+	}
+
+	assertStringArray(t, opts.Files, []string{"x", "y"})
+
+	if !opts.Verbose {
+		t.Errorf("Expected Verbose to be set")
+	}
+
+	if opts.Size != [2]int{640, 480} {
+		t.Errorf("Unexpected size %v", opts.Size)
+	}
+}
+
+func TestNargsErrors(t *testing.T) {
+	var opts = struct {
+		Verbose bool          `short:"v" long:"verbose"`
+		Point   []int         `long:"point" nargs:"2"`
+		Rename  []nargsRename `long:"rename" nargs:"2"`
+	}{}
+
+	assertParseFail(t, ErrExpectedArgument, "expected 2 arguments for flag `"+defaultLongOptDelimiter+"point', but got 1",
+		&opts, "--point", "1", "--verbose")
+
+	assertParseFail(t, ErrExpectedArgument, "expected 2 arguments for flag `"+defaultLongOptDelimiter+"point', but got 1",
+		&opts, "--point", "1")
+
+	assertParseFail(t, ErrMarshal, "invalid argument for flag `"+defaultLongOptDelimiter+"point' (expected []int): strconv.ParseInt: parsing \"x\": invalid syntax",
+		&opts, "--point", "1", "x")
+}
+
+func TestNargsInvalidTag(t *testing.T) {
+	var invalid = struct {
+		Name string `long:"name" nargs:"2"`
+	}{}
+
+	assertParseFail(t, ErrInvalidTag, "nargs tag can only be used with slice, array or struct options, not `name'", &invalid)
+
+	var invalidRange = struct {
+		Point []int `long:"point" nargs:"2-1"`
+	}{}
+
+	assertParseFail(t, ErrInvalidTag, "invalid nargs `2-1'", &invalidRange)
+
+	var tooMany = struct {
+		Rename nargsRename `long:"rename" nargs:"3"`
+	}{}
+
+	assertParseFail(t, ErrInvalidTag, "nargs of `rename' exceeds the number of fields (2)", &tooMany)
+}
+
+func TestNargsHelp(t *testing.T) {
+	var opts = struct {
+		Point  []int       `long:"point" nargs:"2" value-name:"X" value-name:"Y" description:"A point"`
+		Rename nargsRename `long:"rename" nargs:"2" description:"Rename a file"`
+		Files  []string    `long:"files" nargs:"1-2" value-name:"FILE" description:"Some files"`
+	}{}
+
+	p := NewNamedParser("test", None)
+	p.AddGroup("Application Options", "", &opts)
+
+	var b bytes.Buffer
+	p.WriteHelp(&b)
+
+	for _, expected := range []string{
+		defaultLongOptDelimiter + "point X Y",
+		defaultLongOptDelimiter + "rename OLD NEW",
+		defaultLongOptDelimiter + "files FILE [FILE]",
+	} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("Expected help to contain %q, but got:\n%s", expected, b.String())
+		}
+	}
+}
+
+type nargsGreeting string
+
+func (g *nargsGreeting) Complete(match string) []Completion {
+	return (&TestComplete{}).Complete(match)
+}
+
+type nargsCompleted struct {
+	Name     string
+	Greeting nargsGreeting
+}
+
+func TestNargsCompletion(t *testing.T) {
+	var opts = struct {
+		Verbose bool            `short:"v" long:"verbose"`
+		Greet   nargsCompleted  `long:"greet" nargs:"2"`
+		Names   []nargsGreeting `long:"names" nargs:"1-2"`
+	}{}
+
+	p := NewParser(&opts, None)
+	c := &completion{parser: p}
+
+	tests := []struct {
+		args      []string
+		completed []string
+	}{
+		{[]string{"--greet", "hello"}, []string{}},
+		{[]string{"--greet", "x", "hello u"}, []string{"hello universe"}},
+		{[]string{"--greet", "x", "y", "--verb"}, []string{defaultLongOptDelimiter + "verbose"}},
+		{[]string{"--names", "x", "hello w"}, []string{"hello world"}},
+		{[]string{"--names", "x", "--verb"}, []string{defaultLongOptDelimiter + "verbose"}},
+	}
+
+	for _, test := range tests {
+		ret := c.complete(test.args)
+		items := make([]string, len(ret))
+
+		for i, v := range ret {
+			items[i] = v.Item
+		}
+
+		if !reflect.DeepEqual(items, test.completed) {
+			t.Errorf("Args: %#v\n  Expected: %#v\n  Got:     %#v", test.args, test.completed, items)
+		}
+	}
+}
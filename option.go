@@ -2,9 +2,10 @@ package flags
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
-	"os"
 	"reflect"
+	"sort"
 	"strings"
 	"unicode/utf8"
 )
@@ -27,6 +28,14 @@ type Option struct {
 	// to be non-empty.
 	LongName string
 
+	// Additional short names of the option, which can be used instead of
+	// ShortName.
+	ShortAliases []rune
+
+	// Additional long names of the option, which can be used instead of
+	// LongName.
+	LongAliases []string
+
 	// The default value of the option.
 	Default []string
 
@@ -36,6 +45,16 @@ type Option struct {
 	// The optional delimiter string for EnvDefaultKey values.
 	EnvDefaultDelim string
 
+	// The optional environment key name of a file from which the value is
+	// read, if the EnvDefaultKey is not set.
+	EnvFileKey string
+
+	// The optional delimiter string which splits a single value of a slice
+	// or map option into multiple elements, e.g. --tags=a,b,c. Elements
+	// containing the delimiter can be enclosed in double quotes, or the
+	// delimiter can be escaped using a backslash.
+	Delim string
+
 	// If true, specifies that the argument to an option flag is optional.
 	// When no argument to the flag is specified on the command line, the
 	// value of OptionalValue will be set in the field this option represents.
@@ -57,6 +76,21 @@ type Option struct {
 	// A name for the value of an option shown in the Help as --flag [ValueName]
 	ValueName string
 
+	// The names of the values of an option consuming multiple arguments
+	// (see Nargs), shown in the Help as --flag X Y.
+	ValueNames []string
+
+	// The number of arguments consumed by a single occurrence of the
+	// option, e.g. --point X Y. If 0, the option consumes a single
+	// argument. The arguments are appended to slice options, or assigned
+	// to the exported fields of struct options in order.
+	Nargs int
+
+	// The maximum number of arguments consumed by a single occurrence of
+	// the option. Arguments beyond Nargs are optional, and are only
+	// consumed if they are not options themselves.
+	NargsMaximum int
+
 	// A mask value to show in the help instead of the default value. This
 	// is useful for hiding sensitive information in the help, such as
 	// passwords.
@@ -68,6 +102,16 @@ type Option struct {
 	// If true, the option is not displayed in the help or man page
 	Hidden bool
 
+	// If true, the value of the option is a secret, which is masked in the
+	// help message, errors and ini files.
+	Secret bool
+
+	// The prefix character of the option. If not 0, the option can only
+	// be specified using the prefix character instead of the delimiters
+	// of the option style, e.g. +<ShortName> or +<LongName>. The prefix
+	// needs to be one of the parser's OptionPrefixes.
+	Prefix rune
+
 	// The group which the option belongs to
 	group *Group
 
@@ -86,6 +130,16 @@ type Option struct {
 	preventDefault          bool
 	clearReferenceBeforeSet bool
 
+	// The number of elements of an array option which have been set
+	arrayIndex int
+
+	// Where the value of the option was set from
+	source ValueSource
+
+	// The option whose value is read from a file by this option, see the
+	// file tag
+	fileFor *Option
+
 	defaultLiteral string
 }
 
@@ -94,7 +148,53 @@ type Option struct {
 // itself are separated by the parser's namespace delimiter. If the long name is
 // empty an empty string is returned.
 func (option *Option) LongNameWithNamespace() string {
+	return option.withNamespace(option.LongName)
+}
+
+// longNamesWithNamespace returns the long name and long aliases of the
+// option with the group namespaces prepended.
+func (option *Option) longNamesWithNamespace() []string {
 	if len(option.LongName) == 0 {
+		return nil
+	}
+
+	names := []string{option.LongNameWithNamespace()}
+
+	for _, alias := range option.LongAliases {
+		names = append(names, option.withNamespace(alias))
+	}
+
+	return names
+}
+
+// shortNames returns the short name and short aliases of the option.
+func (option *Option) shortNames() []rune {
+	if option.ShortName == 0 {
+		return nil
+	}
+
+	return append([]rune{option.ShortName}, option.ShortAliases...)
+}
+
+// helpAliases returns the short and long aliases of the option as shown
+// in the help, each preceded by a comma.
+func (option *Option) helpAliases() (string, string) {
+	var short, long string
+
+	for _, alias := range option.ShortAliases {
+		short += ", " + option.shortDelimiter() + string(alias)
+	}
+
+	for _, alias := range option.LongAliases {
+		long += ", " + option.longDelimiter() + option.withNamespace(alias)
+	}
+
+	return short, long
+}
+
+// withNamespace prepends the group namespaces to the given long name.
+func (option *Option) withNamespace(name string) string {
+	if len(name) == 0 {
 		return ""
 	}
 
@@ -119,7 +219,7 @@ func (option *Option) LongNameWithNamespace() string {
 	}
 
 	// concatenate long name with namespace
-	longName := option.LongName
+	longName := name
 	g = option.group
 
 	for g != nil {
@@ -142,13 +242,43 @@ func (option *Option) LongNameWithNamespace() string {
 
 // EnvKeyWithNamespace returns the option's env key with the group namespaces
 // prepended by walking up the option's group tree. Namespaces and the env key
-// itself are separated by the parser's namespace delimiter. If the env key is
-// empty an empty string is returned.
+// itself are separated by the parser's namespace delimiter. If the option has
+// no env key, the key derived from its long name is used when the parser has
+// an EnvPrefix or EnvNaming (prefixed by EnvPrefix). If there is no env key,
+// an empty string is returned.
 func (option *Option) EnvKeyWithNamespace() string {
-	if len(option.EnvDefaultKey) == 0 {
+	if len(option.EnvDefaultKey) != 0 {
+		return option.envWithNamespace(option.EnvDefaultKey)
+	}
+
+	key := option.automaticEnvKey()
+
+	if len(key) == 0 {
+		return ""
+	}
+
+	key = option.envWithNamespace(key)
+
+	if p := option.parser(); p.EnvPrefix != "" {
+		key = p.EnvPrefix + p.EnvNamespaceDelimiter + key
+	}
+
+	return key
+}
+
+// envFileKeyWithNamespace returns the option's env file key with the group
+// namespaces prepended, or an empty string if the option has no env file key.
+func (option *Option) envFileKeyWithNamespace() string {
+	if len(option.EnvFileKey) == 0 {
 		return ""
 	}
 
+	return option.envWithNamespace(option.EnvFileKey)
+}
+
+// envWithNamespace returns the given env key with the env namespaces of the
+// option's groups prepended.
+func (option *Option) envWithNamespace(key string) string {
 	// fetch the namespace delimiter from the parser which is always at the
 	// end of the group hierarchy
 	namespaceDelimiter := ""
@@ -170,7 +300,6 @@ func (option *Option) EnvKeyWithNamespace() string {
 	}
 
 	// concatenate long name with namespace
-	key := option.EnvDefaultKey
 	g = option.group
 
 	for g != nil {
@@ -191,6 +320,12 @@ func (option *Option) EnvKeyWithNamespace() string {
 	return key
 }
 
+// parser returns the parser the option belongs to, or nil if the option has
+// not been added to a parser.
+func (option *Option) parser() *Parser {
+	return option.group.parser()
+}
+
 // String converts an option to a human friendly readable string describing the
 // option.
 func (option *Option) String() string {
@@ -204,13 +339,13 @@ func (option *Option) String() string {
 
 		if len(option.LongName) != 0 {
 			s = fmt.Sprintf("%s%s, %s%s",
-				string(defaultShortOptDelimiter), short,
-				defaultLongOptDelimiter, option.LongNameWithNamespace())
+				option.shortDelimiter(), short,
+				option.longDelimiter(), option.LongNameWithNamespace())
 		} else {
-			s = fmt.Sprintf("%s%s", string(defaultShortOptDelimiter), short)
+			s = fmt.Sprintf("%s%s", option.shortDelimiter(), short)
 		}
 	} else if len(option.LongName) != 0 {
-		s = fmt.Sprintf("%s%s", defaultLongOptDelimiter, option.LongNameWithNamespace())
+		s = fmt.Sprintf("%s%s", option.longDelimiter(), option.LongNameWithNamespace())
 	}
 
 	return s
@@ -231,6 +366,25 @@ func (option *Option) IsSet() bool {
 	return option.isSet
 }
 
+// Source returns where the value of the option was set from.
+func (option *Option) Source() ValueSource {
+	return option.source
+}
+
+// setSource records where the value of the option was set from, and
+// updates the source of Optional values accordingly.
+func (option *Option) setSource(source ValueSource) {
+	option.source = source
+
+	if !option.value.CanAddr() {
+		return
+	}
+
+	if o, ok := asOptional(option.value); ok {
+		o.setOptionalSource(source)
+	}
+}
+
 // IsSetDefault returns true if option has been set via the default option tag
 func (option *Option) IsSetDefault() bool {
 	return option.isSetDefault
@@ -240,46 +394,272 @@ func (option *Option) IsSetDefault() bool {
 // if the specified value could not be converted to the corresponding option
 // value type.
 func (option *Option) Set(value *string) error {
+	option.markSet()
+
+	if value == nil {
+		return option.set(nil)
+	}
+
+	parts, err := option.splitValue(*value)
+
+	if err != nil {
+		return err
+	}
+
+	for i := range parts {
+		if err := option.set(&parts[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// markSet marks the option as set, clearing the values of slice, array
+// and map options when they are set for the first time.
+func (option *Option) markSet() {
 	kind := option.value.Type().Kind()
 
-	if (kind == reflect.Map || kind == reflect.Slice) && option.clearReferenceBeforeSet {
+	if (kind == reflect.Map || kind == reflect.Slice || kind == reflect.Array || option.isOptional()) && option.clearReferenceBeforeSet {
 		option.empty()
 	}
 
 	option.isSet = true
 	option.preventDefault = true
 	option.clearReferenceBeforeSet = false
+}
 
-	if len(option.Choices) != 0 {
-		found := false
+// setNargs sets the arguments consumed by a single occurrence of an option
+// with multiple arguments (see Nargs).
+func (option *Option) setNargs(values []string) error {
+	tp := option.nargsStructType()
 
-		for _, choice := range option.Choices {
-			if choice == *value {
-				found = true
-				break
+	if tp == nil {
+		for i := range values {
+			if err := option.Set(&values[i]); err != nil {
+				return err
 			}
 		}
 
-		if !found {
-			allowed := strings.Join(option.Choices[0:len(option.Choices)-1], ", ")
+		return nil
+	}
+
+	keys, err := structKeys(tp)
+
+	if err != nil {
+		return err
+	}
+
+	names := option.nargsValueNames()
+	val := reflect.New(tp).Elem()
 
-			if len(option.Choices) > 1 {
-				allowed += " or " + option.Choices[len(option.Choices)-1]
+	for i, key := range keys {
+		field := val.Field(key.index)
+
+		if i < len(values) {
+			if err := convert(option.parser(), values[i], field, key.tag); err != nil {
+				p := option.parser()
+				return errors.New(p.Message(MsgInvalidNargsValue, values[i], names[i], p.errorMessage(err)))
+			}
+		} else {
+			for _, def := range key.defaults {
+				if err := convert(option.parser(), def, field, key.tag); err != nil {
+					return err
+				}
 			}
+		}
+	}
+
+	option.markSet()
 
-			return newErrorf(ErrInvalidChoice,
-				"Invalid value `%s' for option `%s'. Allowed values are: %s",
-				*value, option, allowed)
+	if option.value.Kind() == reflect.Slice {
+		option.value.Set(reflect.Append(option.value, val))
+	} else {
+		option.value.Set(val)
+	}
+
+	return nil
+}
+
+// nargsStructType returns the struct type whose fields are set from the
+// arguments of an option with multiple arguments, or nil if the arguments
+// are set individually.
+func (option *Option) nargsStructType() reflect.Type {
+	if option.Nargs == 0 {
+		return nil
+	}
+
+	tp := option.value.Type()
+
+	if tp.Kind() == reflect.Slice {
+		tp = tp.Elem()
+	}
+
+	if tp.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return structOptionType(tp)
+}
+
+// nargsValueNames returns the names of the arguments of an option with
+// multiple arguments. Names are taken from ValueNames, the keys of struct
+// options, or ValueName, in that order.
+func (option *Option) nargsValueNames() []string {
+	var keys []structKey
+
+	if tp := option.nargsStructType(); tp != nil {
+		keys, _ = structKeys(tp)
+	}
+
+	names := make([]string, option.NargsMaximum)
+
+	for i := range names {
+		switch {
+		case i < len(option.ValueNames):
+			names[i] = option.ValueNames[i]
+		case i < len(keys):
+			names[i] = strings.ToUpper(keys[i].name)
+		case option.ValueName != "":
+			names[i] = option.ValueName
+		default:
+			names[i] = strings.ToUpper(option.field.Name)
 		}
 	}
 
+	return names
+}
+
+// helpValueName returns the name of the option value shown in the help.
+// The names of the optional arguments of options with multiple arguments
+// are enclosed in brackets, e.g. X Y [Z].
+func (option *Option) helpValueName() string {
+	if option.Nargs == 0 {
+		return option.ValueName
+	}
+
+	names := option.nargsValueNames()
+
+	for i := option.Nargs; i < len(names); i++ {
+		names[i] = "[" + names[i] + "]"
+	}
+
+	return strings.Join(names, " ")
+}
+
+// argumentValue returns a value of the type of the given argument of the
+// option, used to complete the argument.
+func (option *Option) argumentValue(index int) reflect.Value {
+	tp := option.nargsStructType()
+
+	if tp == nil {
+		return option.value
+	}
+
+	keys, _ := structKeys(tp)
+
+	if index >= len(keys) {
+		return option.value
+	}
+
+	return reflect.New(tp.Field(keys[index].index).Type).Elem()
+}
+
+// checkNargs checks that an option with multiple arguments has a type which
+// can hold them.
+func (option *Option) checkNargs() error {
+	if option.Nargs == 0 {
+		return nil
+	}
+
+	kind := option.value.Kind()
+
+	if kind != reflect.Slice && kind != reflect.Array && option.nargsStructType() == nil {
+		return newErrorf(ErrInvalidTag,
+			"nargs tag can only be used with slice, array or struct options, not `%s'",
+			option.shortAndLongName())
+	}
+
+	if tp := option.nargsStructType(); tp != nil {
+		keys, err := structKeys(tp)
+
+		if err != nil {
+			return err
+		}
+
+		if option.NargsMaximum > len(keys) {
+			return newErrorf(ErrInvalidTag,
+				"nargs of `%s' exceeds the number of fields (%d)",
+				option.shortAndLongName(), len(keys))
+		}
+	}
+
+	return nil
+}
+
+// nargs returns the minimum and maximum number of arguments consumed by a
+// single occurrence of the option.
+func (option *Option) nargs() (int, int) {
+	if option.Nargs == 0 {
+		return 1, 1
+	}
+
+	return option.Nargs, option.NargsMaximum
+}
+
+// set sets a single element of the option value.
+func (option *Option) set(value *string) error {
+	if len(option.Choices) != 0 {
+		p := option.parser()
+		choice, found := p.matchChoice(option.Choices, *value)
+
+		if !found {
+			return p.newError(ErrInvalidChoice, MsgInvalidChoice,
+				option.maskSecret(*value), option, p.joinList(option.Choices))
+		}
+
+		value = &choice
+	}
+
 	if option.isFunc() {
 		return option.call(value)
-	} else if value != nil {
-		return convert(*value, option.value, option.tag)
 	}
 
-	return convert("", option.value, option.tag)
+	var val string
+
+	if value != nil {
+		val = *value
+	}
+
+	if option.value.Kind() == reflect.Array {
+		return option.setElement(val)
+	}
+
+	return convert(option.parser(), val, option.value, option.tag)
+}
+
+// setElement sets the next element of an array option.
+func (option *Option) setElement(value string) error {
+	if option.arrayIndex >= option.value.Len() {
+		return option.parser().newError(ErrMarshal, MsgTooManyValues, option, option.value.Len())
+	}
+
+	if err := convert(option.parser(), value, option.value.Index(option.arrayIndex), option.tag); err != nil {
+		return err
+	}
+
+	option.arrayIndex++
+	return nil
+}
+
+// checkLength returns an error if an array option has been set, but not
+// with exactly as many values as the array has elements.
+func (option *Option) checkLength() error {
+	if option.value.Kind() != reflect.Array || option.arrayIndex == 0 || option.arrayIndex == option.value.Len() {
+		return nil
+	}
+
+	return option.parser().newError(ErrExpectedArgument, MsgTooFewValues, option.value.Len(), option, option.arrayIndex)
 }
 
 func (option *Option) setDefault(value *string) error {
@@ -302,7 +682,7 @@ func (option *Option) showInHelp() bool {
 }
 
 func (option *Option) canArgument() bool {
-	if u := option.isUnmarshaler(); u != nil {
+	if option.isUnmarshaler() {
 		return true
 	}
 
@@ -323,6 +703,8 @@ func (option *Option) empty() {
 	if !option.isFunc() {
 		option.value.Set(option.emptyValue())
 	}
+
+	option.arrayIndex = 0
 }
 
 func (option *Option) clearDefault() error {
@@ -330,10 +712,13 @@ func (option *Option) clearDefault() error {
 		return nil
 	}
 
-	usedDefault := option.Default
+	var usedDefault []string
+	source := SourceDefault
 
 	if envKey := option.EnvKeyWithNamespace(); envKey != "" {
-		if value, ok := os.LookupEnv(envKey); ok {
+		if value, ok := option.parser().lookupEnv(envKey); ok {
+			source = SourceEnv
+
 			if option.EnvDefaultDelim != "" {
 				usedDefault = strings.Split(value, option.EnvDefaultDelim)
 			} else {
@@ -342,6 +727,31 @@ func (option *Option) clearDefault() error {
 		}
 	}
 
+	if envKey := option.envFileKeyWithNamespace(); envKey != "" && source == SourceDefault {
+		if filename, ok := option.parser().lookupEnv(envKey); ok {
+			value, err := option.readFile(filename)
+
+			if err != nil {
+				return err
+			}
+
+			source = SourceEnv
+			usedDefault = []string{value}
+		}
+	}
+
+	if source == SourceDefault {
+		var err error
+
+		if usedDefault, err = option.defaults(); err != nil {
+			return err
+		}
+
+		if usedDefault, err = option.expandDefaults(usedDefault); err != nil {
+			return err
+		}
+	}
+
 	option.isSetDefault = true
 
 	if len(usedDefault) > 0 {
@@ -354,6 +764,8 @@ func (option *Option) clearDefault() error {
 				return err
 			}
 		}
+
+		option.setSource(source)
 	} else {
 		tp := option.value.Type()
 
@@ -382,16 +794,29 @@ func (option *Option) valueIsDefault() bool {
 
 	checkval.Set(emptyval)
 
-	if len(option.Default) != 0 {
-		for _, v := range option.Default {
-			convert(v, checkval, option.tag)
+	var index int
+
+	defs, _ := option.defaults()
+	defs, _ = option.expandDefaults(defs)
+
+	for _, v := range defs {
+		parts, _ := option.splitValue(v)
+
+		for _, part := range parts {
+			if checkval.Kind() != reflect.Array {
+				convert(option.parser(), part, checkval, option.tag)
+			} else if index < checkval.Len() {
+				convert(option.parser(), part, checkval.Index(index), option.tag)
+				index++
+			}
 		}
 	}
 
-	return reflect.DeepEqual(option.value.Interface(), checkval.Interface())
+	// The source of Optional values is not part of the value itself
+	return reflect.DeepEqual(unwrapOptional(option.value).Interface(), unwrapOptional(checkval).Interface())
 }
 
-func (option *Option) isUnmarshaler() Unmarshaler {
+func (option *Option) isUnmarshaler() bool {
 	v := option.value
 
 	for {
@@ -399,10 +824,8 @@ func (option *Option) isUnmarshaler() Unmarshaler {
 			break
 		}
 
-		i := v.Interface()
-
-		if u, ok := i.(Unmarshaler); ok {
-			return u
+		if unmarshalFunc(v.Interface()) != nil {
+			return true
 		}
 
 		if !v.CanAddr() {
@@ -412,7 +835,7 @@ func (option *Option) isUnmarshaler() Unmarshaler {
 		v = v.Addr()
 	}
 
-	return nil
+	return false
 }
 
 func (option *Option) isValueValidator() ValueValidator {
@@ -439,12 +862,31 @@ func (option *Option) isValueValidator() ValueValidator {
 	return nil
 }
 
+// choiceDescription returns the description of the given choice, if the
+// option has an enum type which describes its values.
+func (option *Option) choiceDescription(choice string) string {
+	if enum := enumElemOf(option.value.Type()); enum != nil {
+		return enumDescription(enum, choice)
+	}
+
+	return ""
+}
+
+func (option *Option) isOptional() bool {
+	return optionalElemType(option.value.Type()) != nil
+}
+
 func (option *Option) isBool() bool {
 	tp := option.value.Type()
 
 	for {
+		if elem := optionalElemType(tp); elem != nil {
+			tp = elem
+			continue
+		}
+
 		switch tp.Kind() {
-		case reflect.Slice, reflect.Ptr:
+		case reflect.Slice, reflect.Array, reflect.Ptr:
 			tp = tp.Elem()
 		case reflect.Bool:
 			return true
@@ -460,8 +902,13 @@ func (option *Option) isSignedNumber() bool {
 	tp := option.value.Type()
 
 	for {
+		if elem := optionalElemType(tp); elem != nil {
+			tp = elem
+			continue
+		}
+
 		switch tp.Kind() {
-		case reflect.Slice, reflect.Ptr:
+		case reflect.Slice, reflect.Array, reflect.Ptr:
 			tp = tp.Elem()
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
 			return true
@@ -486,7 +933,7 @@ func (option *Option) call(value *string) error {
 		val := reflect.New(tp)
 		val = reflect.Indirect(val)
 
-		if err := convert(*value, val, option.tag); err != nil {
+		if err := convert(option.parser(), *value, val, option.tag); err != nil {
 			return err
 		}
 
@@ -505,7 +952,7 @@ func (option *Option) call(value *string) error {
 }
 
 func (option *Option) updateDefaultLiteral() {
-	defs := option.Default
+	defs, _ := option.defaults()
 	def := ""
 
 	if len(defs) == 0 && option.canArgument() {
@@ -514,7 +961,7 @@ func (option *Option) updateDefaultLiteral() {
 		switch option.field.Type.Kind() {
 		case reflect.Func, reflect.Ptr:
 			showdef = !option.value.IsNil()
-		case reflect.Slice, reflect.String, reflect.Array:
+		case reflect.Slice, reflect.String:
 			showdef = option.value.Len() > 0
 		case reflect.Map:
 			showdef = !option.value.IsNil() && option.value.Len() > 0
@@ -523,9 +970,13 @@ func (option *Option) updateDefaultLiteral() {
 			showdef = !reflect.DeepEqual(zeroval.Interface(), option.value.Interface())
 		}
 
-		if showdef {
+		if showdef && option.splitsValues() {
+			def = option.joinedValue()
+		} else if showdef {
 			def, _ = convertToString(option.value, option.tag)
 		}
+	} else if len(defs) != 0 && option.splitsValues() {
+		def = strings.Join(defs, option.Delim)
 	} else if len(defs) != 0 {
 		l := len(defs) - 1
 
@@ -539,11 +990,87 @@ func (option *Option) updateDefaultLiteral() {
 	option.defaultLiteral = def
 }
 
+// shortDelimiter returns the delimiter with which the short name of the
+// option is specified.
+func (option *Option) shortDelimiter() string {
+	if option.Prefix != 0 {
+		return string(option.Prefix)
+	}
+
+	return string(option.parser().shortOptDelimiter())
+}
+
+// longDelimiter returns the delimiter with which the long name of the
+// option is specified.
+func (option *Option) longDelimiter() string {
+	if option.Prefix != 0 {
+		return string(option.Prefix)
+	}
+
+	return option.parser().longOptDelimiter()
+}
+
+// nameArgDelimiter returns the delimiter which separates the name of the
+// option from its argument.
+func (option *Option) nameArgDelimiter() rune {
+	if option.Prefix != 0 {
+		return posixNameArgDelimiter
+	}
+
+	return option.parser().nameArgDelimiter()
+}
+
+// splitsValues returns whether values of the option are split into multiple
+// elements using the option delimiter.
+func (option *Option) splitsValues() bool {
+	if option.Delim == "" || option.isUnmarshaler() {
+		return false
+	}
+
+	kind := option.value.Type().Kind()
+	return kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+}
+
+// splitValue splits value into the elements which are set individually.
+func (option *Option) splitValue(value string) ([]string, error) {
+	if !option.splitsValues() {
+		return []string{value}, nil
+	}
+
+	return splitDelimited(value, option.Delim)
+}
+
+// joinedValue returns the elements of the option value joined by the option
+// delimiter.
+func (option *Option) joinedValue() string {
+	val := option.value
+	var parts []string
+
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			s, _ := convertToString(val.Index(i), option.tag)
+			parts = append(parts, s)
+		}
+	case reflect.Map:
+		for _, key := range val.MapKeys() {
+			k, _ := convertToString(key, option.tag)
+			v, _ := convertToString(val.MapIndex(key), option.tag)
+
+			parts = append(parts, k+":"+v)
+		}
+
+		sort.Strings(parts)
+	}
+
+	return joinDelimited(parts, option.Delim)
+}
+
 func (option *Option) shortAndLongName() string {
 	ret := &bytes.Buffer{}
 
 	if option.ShortName != 0 {
-		ret.WriteRune(defaultShortOptDelimiter)
+		ret.WriteString(option.shortDelimiter())
 		ret.WriteRune(option.ShortName)
 	}
 
@@ -562,8 +1089,8 @@ func (option *Option) isValidValue(arg string) error {
 	if validator := option.isValueValidator(); validator != nil {
 		return validator.IsValidValue(arg)
 	}
-	if argumentIsOption(arg) && !(option.isSignedNumber() && len(arg) > 1 && arg[0] == '-' && arg[1] >= '0' && arg[1] <= '9') {
-		return fmt.Errorf("expected argument for flag `%s', but got option `%s'", option, arg)
+	if option.parser().argumentIsOption(arg) && !(option.isSignedNumber() && len(arg) > 1 && arg[0] == '-' && arg[1] >= '0' && arg[1] <= '9') {
+		return errors.New(option.parser().Message(MsgExpectedArgumentOption, option, option.maskSecret(arg)))
 	}
 	return nil
 }
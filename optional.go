@@ -0,0 +1,133 @@
+package flags
+
+import (
+	"reflect"
+)
+
+// ValueSource describes where the value of an option was set from.
+type ValueSource uint
+
+const (
+	// SourceNone indicates that the value was not set.
+	SourceNone ValueSource = iota
+
+	// SourceDefault indicates that the value was set from the default
+	// value of the option.
+	SourceDefault
+
+	// SourceEnv indicates that the value was set from the environment
+	// variable of the option.
+	SourceEnv
+
+	// SourceIni indicates that the value was set from an ini file.
+	SourceIni
+
+	// SourceCommandLine indicates that the value was set from the command
+	// line.
+	SourceCommandLine
+)
+
+// String returns a description of the value source.
+func (s ValueSource) String() string {
+	switch s {
+	case SourceNone:
+		return "none"
+	case SourceDefault:
+		return "default"
+	case SourceEnv:
+		return "environment"
+	case SourceIni:
+		return "ini"
+	case SourceCommandLine:
+		return "command line"
+	}
+
+	return "unknown"
+}
+
+// Optional is an option value which records whether, and from where, it was
+// set. Unlike a plain value, it distinguishes an option which was given the
+// zero value of T (e.g. --retries=0) from an option which was not given at
+// all. Optional can be used for any type T supported by the parser, e.g.
+//
+//	type Options struct {
+//	    Retries flags.Optional[int] `long:"retries"`
+//	}
+type Optional[T any] struct {
+	value  T
+	source ValueSource
+}
+
+// Get returns the value and whether it was set.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.source != SourceNone
+}
+
+// IsSet returns whether the value was set.
+func (o Optional[T]) IsSet() bool {
+	return o.source != SourceNone
+}
+
+// Source returns where the value was set from.
+func (o Optional[T]) Source() ValueSource {
+	return o.source
+}
+
+func (o *Optional[T]) optionalElem() reflect.Value {
+	return reflect.ValueOf(&o.value).Elem()
+}
+
+func (o *Optional[T]) optionalSource() ValueSource {
+	return o.source
+}
+
+func (o *Optional[T]) setOptionalSource(source ValueSource) {
+	o.source = source
+}
+
+// optionalValue is implemented by pointers to Optional, and gives access to
+// the wrapped value regardless of its type.
+type optionalValue interface {
+	optionalElem() reflect.Value
+	optionalSource() ValueSource
+	setOptionalSource(source ValueSource)
+}
+
+var optionalValueType = reflect.TypeOf((*optionalValue)(nil)).Elem()
+
+// asOptional returns val as an optionalValue if val is an Optional. Values
+// which are not addressable are copied.
+func asOptional(val reflect.Value) (optionalValue, bool) {
+	if !val.IsValid() || optionalElemType(val.Type()) == nil {
+		return nil, false
+	}
+
+	if !val.CanAddr() {
+		ptr := reflect.New(val.Type())
+		ptr.Elem().Set(val)
+
+		val = ptr.Elem()
+	}
+
+	return val.Addr().Interface().(optionalValue), true
+}
+
+// optionalElemType returns the type wrapped by the Optional type tp, or nil
+// if tp is not an Optional.
+func optionalElemType(tp reflect.Type) reflect.Type {
+	if tp.Kind() != reflect.Struct || !reflect.PtrTo(tp).Implements(optionalValueType) {
+		return nil
+	}
+
+	return reflect.New(tp).Interface().(optionalValue).optionalElem().Type()
+}
+
+// unwrapOptional returns the value wrapped by val if val is an Optional, or
+// val itself otherwise.
+func unwrapOptional(val reflect.Value) reflect.Value {
+	if o, ok := asOptional(val); ok {
+		return o.optionalElem()
+	}
+
+	return val
+}
@@ -0,0 +1,120 @@
+package flags
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptional(t *testing.T) {
+	var opts = struct {
+		Retries Optional[int]           `long:"retries"`
+		Name    Optional[string]        `long:"name"`
+		Verbose Optional[bool]          `short:"v"`
+		Timeout Optional[time.Duration] `long:"timeout"`
+	}{}
+
+	assertParseSuccess(t, &opts, "--retries=0", "-v")
+
+	if v, ok := opts.Retries.Get(); !ok || v != 0 {
+		t.Errorf("Expected retries to be set to 0, but got %v (set: %v)", v, ok)
+	}
+
+	if opts.Retries.Source() != SourceCommandLine {
+		t.Errorf("Expected retries to be set from the command line, but got %v", opts.Retries.Source())
+	}
+
+	if opts.Name.IsSet() || opts.Timeout.IsSet() {
+		t.Errorf("Expected name and timeout not to be set")
+	}
+
+	if v, ok := opts.Verbose.Get(); !ok || !v {
+		t.Errorf("Expected verbose to be set to true, but got %v (set: %v)", v, ok)
+	}
+
+	assertParseFail(t, ErrMarshal, "invalid argument for flag `"+defaultLongOptDelimiter+"retries' (expected int): strconv.ParseInt: parsing \"x\": invalid syntax",
+		&opts, "--retries", "x")
+}
+
+func TestOptionalSource(t *testing.T) {
+	oldEnv := EnvSnapshot()
+	defer oldEnv.Restore()
+
+	var opts = struct {
+		Retries Optional[int]    `long:"retries" default:"3"`
+		Name    Optional[string] `long:"name" env:"TEST_OPTIONAL_NAME"`
+		Level   Optional[int]    `long:"level"`
+	}{}
+
+	os.Setenv("TEST_OPTIONAL_NAME", "env")
+
+	p := NewParser(&opts, None)
+
+	err := NewIniParser(p).Parse(strings.NewReader("[Application Options]\nlevel = 2\n"))
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		source   ValueSource
+		expected ValueSource
+	}{
+		{opts.Retries.Source(), SourceDefault},
+		{opts.Name.Source(), SourceEnv},
+		{opts.Level.Source(), SourceIni},
+		{p.FindOptionByLongName("retries").Source(), SourceDefault},
+	}
+
+	for _, test := range tests {
+		if test.source != test.expected {
+			t.Errorf("Expected source %v, but got %v", test.expected, test.source)
+		}
+	}
+
+	if v, _ := opts.Retries.Get(); v != 3 {
+		t.Errorf("Expected retries to be 3, but got %v", v)
+	}
+
+	assertParseSuccess(t, &opts, "--retries", "0")
+
+	if v, _ := opts.Retries.Get(); v != 0 || opts.Retries.Source() != SourceCommandLine {
+		t.Errorf("Expected retries to be 0 from the command line, but got %v from %v", v, opts.Retries.Source())
+	}
+}
+
+func TestOptionalHelp(t *testing.T) {
+	var opts = struct {
+		Retries Optional[int]    `long:"retries" default:"3" description:"Number of retries"`
+		Name    Optional[string] `long:"name" description:"A name"`
+	}{}
+
+	p := NewNamedParser("test", None)
+	p.AddGroup("Application Options", "", &opts)
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var b bytes.Buffer
+	p.WriteHelp(&b)
+
+	if !strings.Contains(b.String(), "Number of retries (default: 3)") {
+		t.Errorf("Expected default in help, but got:\n%s", b.String())
+	}
+
+	if strings.Contains(b.String(), "A name (default") {
+		t.Errorf("Expected no default for unset option, but got:\n%s", b.String())
+	}
+
+	var ini bytes.Buffer
+	NewIniParser(p).Write(&ini, IniIncludeDefaults)
+
+	assertString(t, ini.String(), "[Application Options]\nRetries = 3\nName =\n\n")
+}
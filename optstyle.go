@@ -0,0 +1,261 @@
+package flags
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// OptionStyle determines the syntax used for options on the command line.
+type OptionStyle uint
+
+const (
+	// OptionStylePOSIX accepts POSIX style options, i.e. -v for short
+	// options and --verbose for long options. Option arguments can be
+	// specified using an equals sign (--file=name).
+	OptionStylePOSIX OptionStyle = iota
+
+	// OptionStyleWindows accepts Windows style options, i.e. /v for short
+	// options and /verbose for long options. Option arguments can be
+	// specified using a colon (/file:name).
+	OptionStyleWindows
+
+	// OptionStyleBoth accepts both POSIX and Windows style options. The two
+	// styles cannot be mixed, i.e. /file:name and --file=name are accepted,
+	// but /file=name and --file:name are not. The help message uses the
+	// Windows style.
+	OptionStyleBoth
+)
+
+const (
+	posixShortOptDelimiter = '-'
+	posixLongOptDelimiter  = "--"
+	posixNameArgDelimiter  = '='
+
+	windowsShortOptDelimiter = '/'
+	windowsLongOptDelimiter  = "/"
+	windowsNameArgDelimiter  = ':'
+)
+
+// OptionPrefix describes an additional character with which options can be
+// specified on the command line (see Parser.OptionPrefixes). Options
+// specified using a prefix character are looked up among the options
+// declared with that prefix (see the prefix tag) first, and otherwise among
+// the options without a prefix. A single character following the prefix
+// denotes a short option, multiple characters denote a long option. Short
+// options cannot be clustered.
+type OptionPrefix struct {
+	// Char is the prefix character, e.g. '+'.
+	Char rune
+
+	// Negate specifies that boolean options without a prefix are set to
+	// false, instead of true, when specified using the prefix character.
+	// Other options without a prefix cannot be specified using the prefix
+	// character when Negate is set.
+	Negate bool
+}
+
+func (s OptionStyle) acceptsPOSIX() bool {
+	return s != OptionStyleWindows
+}
+
+func (s OptionStyle) acceptsWindows() bool {
+	return s != OptionStylePOSIX
+}
+
+func (p *Parser) optionStyle() OptionStyle {
+	if p == nil {
+		return defaultOptionStyle
+	}
+
+	return p.OptionStyle
+}
+
+func (p *Parser) shortOptDelimiter() rune {
+	if p.optionStyle().acceptsWindows() {
+		return windowsShortOptDelimiter
+	}
+
+	return posixShortOptDelimiter
+}
+
+func (p *Parser) longOptDelimiter() string {
+	if p.optionStyle().acceptsWindows() {
+		return windowsLongOptDelimiter
+	}
+
+	if p != nil && (p.Options&SingleDashLong) != None {
+		return string(posixShortOptDelimiter)
+	}
+
+	return posixLongOptDelimiter
+}
+
+func (p *Parser) nameArgDelimiter() rune {
+	if p.optionStyle().acceptsWindows() {
+		return windowsNameArgDelimiter
+	}
+
+	return posixNameArgDelimiter
+}
+
+// optionPrefix returns the additional option prefix with which arg starts.
+func (p *Parser) optionPrefix(arg string) (OptionPrefix, bool) {
+	if p == nil || len(arg) == 0 {
+		return OptionPrefix{}, false
+	}
+
+	c, _ := utf8.DecodeRuneInString(arg)
+
+	for _, prefix := range p.OptionPrefixes {
+		if prefix.Char == c {
+			return prefix, true
+		}
+	}
+
+	return OptionPrefix{}, false
+}
+
+func (p *Parser) argumentStartsOption(arg string) bool {
+	style := p.optionStyle()
+
+	if len(arg) == 0 {
+		return false
+	}
+
+	if _, ok := p.optionPrefix(arg); ok {
+		return true
+	}
+
+	return (style.acceptsPOSIX() && arg[0] == '-') || (style.acceptsWindows() && arg[0] == '/')
+}
+
+func (p *Parser) argumentIsOption(arg string) bool {
+	style := p.optionStyle()
+
+	if prefix, ok := p.optionPrefix(arg); ok {
+		return len(arg) > utf8.RuneLen(prefix.Char)
+	}
+
+	// Windows-style options allow front slash for the option
+	// delimiter.
+	if style.acceptsWindows() && len(arg) > 1 && arg[0] == '/' {
+		return true
+	}
+
+	if !style.acceptsPOSIX() {
+		return false
+	}
+
+	if len(arg) > 1 && arg[0] == '-' && arg[1] != '-' {
+		return true
+	}
+
+	if len(arg) > 2 && arg[0] == '-' && arg[1] == '-' && arg[2] != '-' {
+		return true
+	}
+
+	return false
+}
+
+// stripOptionPrefix returns the option without the prefix and whether or
+// not the option is a long option or not.
+func (p *Parser) stripOptionPrefix(optname string) (prefix string, name string, islong bool) {
+	style := p.optionStyle()
+
+	// Options specified using an additional prefix are long options when
+	// more than one character follows the prefix
+	if prefix, ok := p.optionPrefix(optname); ok {
+		n := utf8.RuneLen(prefix.Char)
+		name = optname[n:]
+
+		if pos := strings.IndexRune(name, posixNameArgDelimiter); pos >= 0 {
+			islong = utf8.RuneCountInString(name[:pos]) > 1
+		} else {
+			islong = utf8.RuneCountInString(name) > 1
+		}
+
+		return optname[:n], name, islong
+	}
+
+	// Determine if the argument is a long option or not. Windows
+	// typically supports both long and short options with a single
+	// front slash as the option delimiter, so handle this situation
+	// nicely.
+	possplit := 0
+
+	if style.acceptsPOSIX() && strings.HasPrefix(optname, "--") {
+		possplit = 2
+		islong = true
+	} else if style.acceptsPOSIX() && strings.HasPrefix(optname, "-") {
+		possplit = 1
+		islong = false
+	} else if style.acceptsWindows() && strings.HasPrefix(optname, "/") {
+		possplit = 1
+		islong = len(optname) > 2
+	}
+
+	return optname[:possplit], optname[possplit:], islong
+}
+
+// splitOption attempts to split the passed option into a name and an argument.
+// When there is no argument specified, nil will be returned for it.
+func (p *Parser) splitOption(prefix string, option string, islong bool) (string, string, *string) {
+	if len(option) == 0 {
+		return option, "", nil
+	}
+
+	// Windows typically uses a colon for the option name and argument
+	// delimiter while POSIX typically uses an equals. When both styles
+	// are supported, don't allow the two to be mixed.
+	var pos int
+	var sp string
+
+	if prefix == "/" {
+		sp = string(windowsNameArgDelimiter)
+		pos = strings.Index(option, sp)
+	} else if len(prefix) > 0 {
+		sp = string(posixNameArgDelimiter)
+		pos = strings.Index(option, sp)
+	}
+
+	if (islong && pos >= 0) || (!islong && pos == 1) {
+		rest := option[pos+1:]
+		return option[:pos], sp, &rest
+	}
+
+	return option, "", nil
+}
+
+// addHelpGroup adds a new group that contains default help parameters.
+func (c *Command) addHelpGroup(showHelp func() error) *Group {
+	var ret *Group
+
+	if c.parser().optionStyle().acceptsWindows() {
+		// Windows CLI applications typically use /? for help, so make both
+		// that available as well as the POSIX style h and help.
+		var help struct {
+			ShowHelpWindows func() error `short:"?"`
+			ShowHelpPosix   func() error `short:"h" long:"help"`
+		}
+
+		help.ShowHelpWindows = showHelp
+		help.ShowHelpPosix = showHelp
+
+		ret, _ = c.AddGroup("Help Options", "", &help)
+	} else {
+		var help struct {
+			ShowHelp func() error `short:"h" long:"help"`
+		}
+
+		help.ShowHelp = showHelp
+		ret, _ = c.AddGroup("Help Options", "", &help)
+	}
+
+	ret.isBuiltinHelp = true
+
+	for _, option := range ret.options {
+		option.Description = ret.parser().Message(MsgShowHelp)
+	}
+
+	return ret
+}
@@ -0,0 +1,257 @@
+package flags
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestOptionStyleWindows(t *testing.T) {
+	var opts struct {
+		Verbose bool   `short:"v" long:"verbose"`
+		File    string `short:"f" long:"file"`
+	}
+
+	p := NewParser(&opts, None)
+	p.OptionStyle = OptionStyleWindows
+
+	ret, err := p.ParseArgs([]string{"/v", "/file:name", "--rest"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !opts.Verbose {
+		t.Errorf("Expected Verbose to be true")
+	}
+
+	assertString(t, opts.File, "name")
+	assertStringArray(t, ret, []string{"--rest"})
+}
+
+func TestOptionStylePOSIX(t *testing.T) {
+	var opts struct {
+		Verbose bool   `short:"v" long:"verbose"`
+		File    string `short:"f" long:"file"`
+	}
+
+	p := NewParser(&opts, None)
+	p.OptionStyle = OptionStylePOSIX
+
+	ret, err := p.ParseArgs([]string{"-v", "--file=name", "/v"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !opts.Verbose {
+		t.Errorf("Expected Verbose to be true")
+	}
+
+	assertString(t, opts.File, "name")
+	assertStringArray(t, ret, []string{"/v"})
+}
+
+func TestOptionStyleBoth(t *testing.T) {
+	var opts struct {
+		Verbose bool   `short:"v" long:"verbose"`
+		File    string `short:"f" long:"file"`
+	}
+
+	p := NewParser(&opts, None)
+	p.OptionStyle = OptionStyleBoth
+
+	_, err := p.ParseArgs([]string{"/verbose", "--file=name"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !opts.Verbose {
+		t.Errorf("Expected Verbose to be true")
+	}
+
+	assertString(t, opts.File, "name")
+
+	_, err = p.ParseArgs([]string{"/file=name"})
+	assertError(t, err, ErrUnknownFlag, "unknown flag `file=name'")
+}
+
+func TestOptionStyleHelp(t *testing.T) {
+	var opts struct {
+		File string `short:"f" long:"file" description:"A file"`
+	}
+
+	tests := []struct {
+		style    OptionStyle
+		args     []string
+		expected []string
+	}{
+		{OptionStylePOSIX, []string{"-h"}, []string{"-f, --file=", "-h, --help"}},
+		{OptionStyleWindows, []string{"/?"}, []string{"/f, /file:", "/?", "/h, /help"}},
+	}
+
+	for _, test := range tests {
+		p := NewNamedParser("test", HelpFlag)
+		p.OptionStyle = test.style
+		p.AddGroup("Application Options", "", &opts)
+
+		_, err := p.ParseArgs(test.args)
+
+		if !WroteHelp(err) {
+			t.Fatalf("Expected help error, but got %v", err)
+		}
+
+		var b bytes.Buffer
+		p.WriteHelp(&b)
+
+		for _, e := range test.expected {
+			if !strings.Contains(b.String(), e) {
+				t.Errorf("Expected help to contain %q, but got:\n%s", e, b.String())
+			}
+		}
+	}
+}
+
+func TestOptionPrefixes(t *testing.T) {
+	var opts struct {
+		Verbose   bool   `short:"v" long:"verbose"`
+		Color     bool   `long:"color"`
+		Name      string `short:"n" long:"name"`
+		Extension string `short:"x" long:"ext" prefix:"+"`
+		Trace     bool   `short:"t" prefix:"+"`
+	}
+
+	p := NewParser(&opts, None)
+	p.OptionStyle = OptionStylePOSIX
+	p.OptionPrefixes = []OptionPrefix{{Char: '+'}}
+
+	ret, err := p.ParseArgs([]string{"+verbose", "+ext", "a", "-n", "b", "+t", "+x=c", "rest"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !opts.Verbose || !opts.Trace {
+		t.Errorf("Expected Verbose and Trace to be true")
+	}
+
+	assertString(t, opts.Name, "b")
+	assertString(t, opts.Extension, "c")
+	assertStringArray(t, ret, []string{"rest"})
+
+	_, err = p.ParseArgs([]string{"-x", "a"})
+	assertError(t, err, ErrUnknownFlag, "unknown flag `x'")
+
+	_, err = p.ParseArgs([]string{"--ext", "a"})
+	assertError(t, err, ErrUnknownFlag, "unknown flag `ext'")
+}
+
+func TestOptionPrefixesNegate(t *testing.T) {
+	var opts struct {
+		Verbose bool   `short:"v" long:"verbose"`
+		Name    string `short:"n" long:"name"`
+		Plus    bool   `long:"plus" prefix:"+"`
+	}
+
+	p := NewParser(&opts, None)
+	p.OptionStyle = OptionStylePOSIX
+	p.OptionPrefixes = []OptionPrefix{{Char: '+', Negate: true}}
+
+	_, err := p.ParseArgs([]string{"-v", "+v"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if opts.Verbose {
+		t.Errorf("Expected Verbose to be false")
+	}
+
+	_, err = p.ParseArgs([]string{"+verbose", "-v", "+plus"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !opts.Verbose || !opts.Plus {
+		t.Errorf("Expected Verbose and Plus to be true")
+	}
+
+	_, err = p.ParseArgs([]string{"+n", "a"})
+	assertError(t, err, ErrUnknownFlag, "unknown flag `+n'")
+
+	_, err = p.ParseArgs([]string{"+verbose=true"})
+	assertError(t, err, ErrNoArgumentForBool, "bool flag `-v, --verbose' cannot have an argument")
+}
+
+func TestOptionPrefixesNegateSource(t *testing.T) {
+	var opts struct {
+		Color Optional[bool] `long:"color"`
+	}
+
+	p := NewParser(&opts, None)
+	p.OptionPrefixes = []OptionPrefix{{Char: '+', Negate: true}}
+
+	if _, err := p.ParseArgs([]string{"+color"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if v, ok := opts.Color.Get(); !ok || v {
+		t.Errorf("Expected Color to be set to false, but got %v (set: %v)", v, ok)
+	}
+
+	if opts.Color.Source() != SourceCommandLine {
+		t.Errorf("Expected Color to be set from the command line, but got %v", opts.Color.Source())
+	}
+
+	if source := p.FindOptionByLongName("color").Source(); source != SourceCommandLine {
+		t.Errorf("Expected option to be set from the command line, but got %v", source)
+	}
+}
+
+func TestOptionPrefixesHelp(t *testing.T) {
+	var opts struct {
+		Verbose   bool   `short:"v" long:"verbose" description:"Verbose output"`
+		Extension string `short:"x" long:"ext" prefix:"+" description:"Extension"`
+	}
+
+	p := NewNamedParser("test", None)
+	p.OptionStyle = OptionStylePOSIX
+	p.OptionPrefixes = []OptionPrefix{{Char: '+'}}
+	p.AddGroup("Application Options", "", &opts)
+
+	var b bytes.Buffer
+	p.WriteHelp(&b)
+
+	for _, expected := range []string{"-v, --verbose", "+x, +ext=", "Extension"} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("Expected help to contain %q, but got:\n%s", expected, b.String())
+		}
+	}
+
+	c := &completion{parser: p}
+
+	ret := c.complete([]string{"+e"})
+
+	if len(ret) != 1 || ret[0].Item != "+ext" {
+		t.Errorf("Expected completion of +ext, but got %v", ret)
+	}
+
+	ret = c.complete([]string{"+ve"})
+
+	if len(ret) != 1 || ret[0].Item != "+verbose" {
+		t.Errorf("Expected completion of +verbose, but got %v", ret)
+	}
+}
+
+func TestOptionPrefixesDuplicate(t *testing.T) {
+	var opts struct {
+		Minus bool `short:"x"`
+		Plus  bool `short:"x" prefix:"+"`
+		Other bool `short:"x" prefix:"+"`
+	}
+
+	_, err := NewParser(&opts, None).ParseArgs(nil)
+	assertError(t, err, ErrDuplicatedFlag, "option `+x' uses the same short name as option `+x'")
+}
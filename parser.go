@@ -7,6 +7,7 @@ package flags
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"reflect"
@@ -33,6 +34,30 @@ type Parser struct {
 	// EnvNamespaceDelimiter separates group env namespaces and env keys
 	EnvNamespaceDelimiter string
 
+	// EnvPrefix enables env keys for all options which have a long name
+	// but no env tag (unless they have the no-env tag). The env key is
+	// derived from the long name with namespace using EnvNaming, prefixed
+	// by the env namespaces of the groups and by EnvPrefix, separated by
+	// EnvNamespaceDelimiter. For example, with EnvPrefix "APP" the option
+	// --dry-run can be set using the environment variable APP_DRY_RUN.
+	EnvPrefix string
+
+	// EnvNaming converts long option names to env keys for EnvPrefix, it
+	// defaults to EnvNameUpperSnake. Setting EnvNaming without EnvPrefix
+	// enables env keys without prefix.
+	EnvNaming func(name string) string
+
+	// OptionStyle determines the syntax of options accepted on the command
+	// line and shown in the help message. It defaults to OptionStyleBoth
+	// on Windows (unless built with the forceposix build tag) and to
+	// OptionStylePOSIX otherwise.
+	OptionStyle OptionStyle
+
+	// OptionPrefixes specifies additional characters with which options
+	// can be specified on the command line, besides the ones of the
+	// option style (see OptionPrefix).
+	OptionPrefixes []OptionPrefix
+
 	// UnknownOptionsHandler is a function which gets called when the parser
 	// encounters an unknown option. The function receives the unknown option
 	// name, a SplitArgument which specifies its value if set with an argument
@@ -56,7 +81,29 @@ type Parser struct {
 	// command to be executed when parsing has finished.
 	CommandHandler func(command Commander, args []string) error
 
+	// ExitCodes overrides the exit codes used by ExitCode, Exit and Main
+	// for specific error types. Error types which are not present in the
+	// map use the default exit codes (see ExitCode).
+	ExitCodes map[ErrorType]int
+
+	// Stdout is the writer to which the built-in help message and
+	// completion items are written. If nil, os.Stdout is used.
+	Stdout io.Writer
+
+	// Stderr is the writer to which errors are written. If nil, os.Stderr
+	// is used.
+	Stderr io.Writer
+
+	// Messages is the message catalog used to format user-facing messages,
+	// such as errors and the headings of the built-in help. Messages which
+	// are not provided by the catalog are formatted in English. If nil, all
+	// messages are formatted in English.
+	Messages MessageCatalog
+
 	internalError error
+
+	// Environment variables loaded from dotenv files
+	dotenv map[string]string
 }
 
 // SplitArgument represents the argument value of an option that was passed using
@@ -91,7 +138,7 @@ const (
 	// -h and --help options. When either -h or --help is specified on the
 	// command line, the parser will return the special error of type
 	// ErrHelp. When PrintErrors is also specified, then the help message
-	// will also be automatically printed to os.Stdout.
+	// will also be automatically printed to the parser's Stdout.
 	HelpFlag = 1 << iota
 
 	// PassDoubleDash passes all arguments after a double dash, --, as
@@ -103,9 +150,9 @@ const (
 	// remaining command line arguments instead of generating an error.
 	IgnoreUnknown
 
-	// PrintErrors prints any errors which occurred during parsing to
-	// os.Stderr. In the special case of ErrHelp, the message will be printed
-	// to os.Stdout.
+	// PrintErrors prints any errors which occurred during parsing to the
+	// parser's Stderr. In the special case of ErrHelp, the message will be
+	// printed to the parser's Stdout.
 	PrintErrors
 
 	// PassAfterNonOption passes all arguments after the first non option
@@ -113,6 +160,44 @@ const (
 	// POSIX processing.
 	PassAfterNonOption
 
+	// SingleDashLong allows long options to be specified using a single
+	// dash (e.g. -verbose or -port=80), as done by the standard library
+	// flag package. Arguments with a single dash are first matched against
+	// the long option names, and otherwise interpreted as a single short
+	// option (with an optional concatenated argument). Clustering of
+	// short options (e.g. -aux) is disabled in this mode. The help message
+	// shows long options with a single dash.
+	SingleDashLong
+
+	// IgnoreCase matches long option names case insensitively, e.g.
+	// --Format is equivalent to --format.
+	IgnoreCase
+
+	// NormalizeNames matches long option names regardless of whether words
+	// are separated by dashes, underscores or camelCase, e.g. --dry_run
+	// and --dryRun are equivalent to --dry-run.
+	NormalizeNames
+
+	// IgnoreChoiceCase matches the values of options with choices case
+	// insensitively. The value of the option is set to the matching choice.
+	IgnoreChoiceCase
+
+	// ExpandVariables expands references to environment variables
+	// (${NAME}) and to the values of other options (${section.key}) in
+	// default values and ini values. Use $$ for a literal dollar sign.
+	ExpandVariables
+
+	// WarnUnknownEnv prints a warning for every environment variable
+	// starting with the EnvPrefix (followed by the EnvNamespaceDelimiter)
+	// which does not correspond to the env key of any option.
+	WarnUnknownEnv
+
+	// ErrorOnUnknownEnv fails parsing with an error of type ErrUnknownEnv
+	// when an environment variable starting with the EnvPrefix (followed
+	// by the EnvNamespaceDelimiter) does not correspond to the env key of
+	// any option.
+	ErrorOnUnknownEnv
+
 	// Default is a convenient default set of options which should cover
 	// most of the uses of the flags package.
 	Default = HelpFlag | PrintErrors | PassDoubleDash
@@ -125,6 +210,10 @@ type parseState struct {
 	positional []*Arg
 	err        error
 
+	// The number of values assigned to the first positional argument, if
+	// it is an array
+	positionalIndex int
+
 	command *Command
 	lookup  lookup
 }
@@ -161,6 +250,7 @@ func NewParser(data interface{}, options Options) *Parser {
 
 		if err == nil {
 			g.parent = p
+			g.isApplicationOptions = true
 		}
 
 		p.internalError = err
@@ -178,6 +268,7 @@ func NewNamedParser(appname string, options Options) *Parser {
 		Options:               options,
 		NamespaceDelimiter:    ".",
 		EnvNamespaceDelimiter: "_",
+		OptionStyle:           defaultOptionStyle,
 	}
 
 	p.Command.parent = p
@@ -226,7 +317,7 @@ func (p *Parser) ParseArgs(args []string) ([]string, error) {
 		if p.CompletionHandler != nil {
 			p.CompletionHandler(items)
 		} else {
-			comp.print(items, compval == "verbose")
+			comp.print(p.stdout(), items, compval == "verbose")
 			os.Exit(0)
 		}
 
@@ -251,7 +342,7 @@ func (p *Parser) ParseArgs(args []string) ([]string, error) {
 			break
 		}
 
-		if !argumentIsOption(arg) {
+		if !p.argumentIsOption(arg) {
 			if (p.Options&PassAfterNonOption) != None && s.lookup.commands[arg] == nil {
 				// If PassAfterNonOption is set then all remaining arguments
 				// are considered positional
@@ -275,10 +366,18 @@ func (p *Parser) ParseArgs(args []string) ([]string, error) {
 			continue
 		}
 
-		prefix, optname, islong := stripOptionPrefix(arg)
-		optname, _, argument := splitOption(prefix, optname, islong)
+		prefix, optname, islong := p.stripOptionPrefix(arg)
+		optionPrefix, isPrefixed := p.optionPrefix(prefix)
 
-		if islong {
+		if !isPrefixed && !islong && p.isSingleDashLong(s, prefix, optname) {
+			islong = true
+		}
+
+		optname, _, argument := p.splitOption(prefix, optname, islong)
+
+		if isPrefixed {
+			err = p.parsePrefixed(s, optionPrefix, optname, islong, argument)
+		} else if islong {
 			err = p.parseLong(s, optname, argument)
 		} else {
 			err = p.parseShort(s, optname, argument)
@@ -308,9 +407,16 @@ func (p *Parser) ParseArgs(args []string) ([]string, error) {
 		}
 	}
 
+	if s.err == nil {
+		s.err = p.checkEnv()
+	}
+
 	if s.err == nil {
 		p.eachOption(func(c *Command, g *Group, option *Option) {
 			err := option.clearDefault()
+			if err == nil {
+				err = option.checkLength()
+			}
 			if err != nil {
 				if _, ok := err.(*Error); !ok {
 					err = p.marshalError(option, err)
@@ -322,12 +428,16 @@ func (p *Parser) ParseArgs(args []string) ([]string, error) {
 		s.checkRequired(p)
 	}
 
+	if s.err == nil {
+		s.err = p.validate()
+	}
+
 	var reterr error
 
 	if s.err != nil {
 		reterr = s.err
 	} else if len(s.command.commands) != 0 && !s.command.SubcommandsOptional {
-		reterr = s.estimateCommand()
+		reterr = s.estimateCommand(p)
 	} else if cmd, ok := s.command.data.(Commander); ok {
 		if p.CommandHandler != nil {
 			reterr = p.CommandHandler(cmd, s.retargs)
@@ -397,39 +507,50 @@ func (p *parseState) checkRequired(parser *Parser) error {
 		if len(p.positional) > 0 {
 			var reqnames []string
 
-			for _, arg := range p.positional {
+			for i, arg := range p.positional {
 				argRequired := (!arg.isRemaining() && p.command.ArgsRequired) || arg.Required != -1 || arg.RequiredMaximum != -1
 
-				if !argRequired {
+				// Array arguments need to be given all of their values
+				// once the first one is given
+				partial := i == 0 && arg.isArray() && p.positionalIndex > 0
+
+				if !argRequired && !partial {
 					continue
 				}
 
 				if arg.isRemaining() {
 					if arg.value.Len() < arg.Required {
-						var arguments string
+						var name string
 
 						if arg.Required > 1 {
-							arguments = "arguments, but got only " + fmt.Sprintf("%d", arg.value.Len())
+							name = parser.Message(MsgAtLeastArguments, arg.Name, arg.Required, arg.value.Len())
 						} else {
-							arguments = "argument"
+							name = parser.Message(MsgAtLeastArgument, arg.Name, arg.Required)
 						}
 
-						reqnames = append(reqnames, "`"+arg.Name+" (at least "+fmt.Sprintf("%d", arg.Required)+" "+arguments+")`")
+						reqnames = append(reqnames, "`"+name+"`")
 					} else if arg.RequiredMaximum != -1 && arg.value.Len() > arg.RequiredMaximum {
+						var name string
+
 						if arg.RequiredMaximum == 0 {
-							reqnames = append(reqnames, "`"+arg.Name+" (zero arguments)`")
+							name = parser.Message(MsgZeroArguments, arg.Name)
+						} else if arg.RequiredMaximum > 1 {
+							name = parser.Message(MsgAtMostArguments, arg.Name, arg.RequiredMaximum, arg.value.Len())
 						} else {
-							var arguments string
+							name = parser.Message(MsgAtMostArgument, arg.Name, arg.RequiredMaximum)
+						}
 
-							if arg.RequiredMaximum > 1 {
-								arguments = "arguments, but got " + fmt.Sprintf("%d", arg.value.Len())
-							} else {
-								arguments = "argument"
-							}
+						reqnames = append(reqnames, "`"+name+"`")
+					}
+				} else if arg.isArray() {
+					var given int
 
-							reqnames = append(reqnames, "`"+arg.Name+" (at most "+fmt.Sprintf("%d", arg.RequiredMaximum)+" "+arguments+")`")
-						}
+					if i == 0 {
+						given = p.positionalIndex
 					}
+
+					name := parser.Message(MsgExactlyArguments, arg.Name, arg.value.Len(), given)
+					reqnames = append(reqnames, "`"+name+"`")
 				} else {
 					reqnames = append(reqnames, "`"+arg.Name+"`")
 				}
@@ -439,16 +560,13 @@ func (p *parseState) checkRequired(parser *Parser) error {
 				return nil
 			}
 
-			var msg string
-
 			if len(reqnames) == 1 {
-				msg = fmt.Sprintf("the required argument %s was not provided", reqnames[0])
+				p.err = parser.newError(ErrRequired, MsgRequiredArgument, reqnames[0])
 			} else {
-				msg = fmt.Sprintf("the required arguments %s and %s were not provided",
+				p.err = parser.newError(ErrRequired, MsgRequiredArguments,
 					strings.Join(reqnames[:len(reqnames)-1], ", "), reqnames[len(reqnames)-1])
 			}
 
-			p.err = newError(ErrRequired, msg)
 			return p.err
 		}
 
@@ -463,20 +581,17 @@ func (p *parseState) checkRequired(parser *Parser) error {
 
 	sort.Strings(names)
 
-	var msg string
-
 	if len(names) == 1 {
-		msg = fmt.Sprintf("the required flag %s was not specified", names[0])
+		p.err = parser.newError(ErrRequired, MsgRequiredFlag, names[0])
 	} else {
-		msg = fmt.Sprintf("the required flags %s and %s were not specified",
+		p.err = parser.newError(ErrRequired, MsgRequiredFlags,
 			strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
 	}
 
-	p.err = newError(ErrRequired, msg)
 	return p.err
 }
 
-func (p *parseState) estimateCommand() error {
+func (p *parseState) estimateCommand(parser *Parser) error {
 	commands := p.command.sortedVisibleCommands()
 	cmdnames := make([]string, len(commands))
 
@@ -489,17 +604,17 @@ func (p *parseState) estimateCommand() error {
 
 	if len(p.retargs) != 0 {
 		c, l := closestChoice(p.retargs[0], cmdnames)
-		msg = fmt.Sprintf("Unknown command `%s'", p.retargs[0])
+		msg = parser.Message(MsgUnknownCommand, p.retargs[0])
 		errtype = ErrUnknownCommand
 
 		if float32(l)/float32(len(c)) < 0.5 {
-			msg = fmt.Sprintf("%s, did you mean `%s'?", msg, c)
+			msg = parser.Message(MsgDidYouMean, msg, c)
 		} else if len(cmdnames) == 1 {
-			msg = fmt.Sprintf("%s. You should use the %s command",
+			msg = parser.Message(MsgUseCommand,
 				msg,
 				cmdnames[0])
 		} else if len(cmdnames) > 1 {
-			msg = fmt.Sprintf("%s. Please specify one command of: %s or %s",
+			msg = parser.Message(MsgSpecifyOneCommandOf,
 				msg,
 				strings.Join(cmdnames[:len(cmdnames)-1], ", "),
 				cmdnames[len(cmdnames)-1])
@@ -508,9 +623,9 @@ func (p *parseState) estimateCommand() error {
 		errtype = ErrCommandRequired
 
 		if len(cmdnames) == 1 {
-			msg = fmt.Sprintf("Please specify the %s command", cmdnames[0])
+			msg = parser.Message(MsgCommandRequired, cmdnames[0])
 		} else if len(cmdnames) > 1 {
-			msg = fmt.Sprintf("Please specify one command of: %s or %s",
+			msg = parser.Message(MsgOneCommandRequired,
 				strings.Join(cmdnames[:len(cmdnames)-1], ", "),
 				cmdnames[len(cmdnames)-1])
 		}
@@ -522,10 +637,12 @@ func (p *parseState) estimateCommand() error {
 func (p *Parser) parseOption(s *parseState, name string, option *Option, canarg bool, argument *string) (err error) {
 	if !option.canArgument() {
 		if argument != nil {
-			return newErrorf(ErrNoArgumentForBool, "bool flag `%s' cannot have an argument", option)
+			return p.newError(ErrNoArgumentForBool, MsgBoolArgument, option)
 		}
 
 		err = option.Set(nil)
+	} else if option.Nargs != 0 {
+		err = p.parseNargs(s, option, canarg, argument)
 	} else if argument != nil || (canarg && !s.eof()) {
 		var arg string
 
@@ -537,11 +654,12 @@ func (p *Parser) parseOption(s *parseState, name string, option *Option, canarg
 			if validationErr := option.isValidValue(arg); validationErr != nil {
 				return newErrorf(ErrExpectedArgument, validationErr.Error())
 			} else if p.Options&PassDoubleDash != 0 && arg == "--" {
-				return newErrorf(ErrExpectedArgument, "expected argument for flag `%s', but got double dash `--'", option)
+				return p.newError(ErrExpectedArgument, MsgExpectedArgumentDoubleDash, option)
 			}
 		}
 
-		if option.tag.Get("unquote") != "false" {
+		// Delimited values handle quoted elements themselves
+		if option.tag.Get("unquote") != "false" && !option.splitsValues() {
 			arg, err = unquoteIfPossible(arg)
 		}
 
@@ -559,36 +677,93 @@ func (p *Parser) parseOption(s *parseState, name string, option *Option, canarg
 			}
 		}
 	} else {
-		err = newErrorf(ErrExpectedArgument, "expected argument for flag `%s'", option)
+		err = p.newError(ErrExpectedArgument, MsgExpectedArgument, option)
 	}
 
 	if err != nil {
 		if _, ok := err.(*Error); !ok {
 			err = p.marshalError(option, err)
 		}
+	} else {
+		option.setSource(SourceCommandLine)
 	}
 
 	return err
 }
 
+// parseNargs parses the arguments of an option consuming multiple arguments.
+// The first argument may be concatenated with the option (e.g. --point=1 2).
+func (p *Parser) parseNargs(s *parseState, option *Option, canarg bool, argument *string) error {
+	var args []string
+
+	if argument != nil {
+		args = append(args, *argument)
+	}
+
+	for canarg && len(args) < option.NargsMaximum && !s.eof() {
+		arg := s.peek()
+
+		if option.isValidValue(arg) != nil || (p.Options&PassDoubleDash != 0 && arg == "--") {
+			break
+		}
+
+		args = append(args, s.pop())
+	}
+
+	if len(args) < option.Nargs {
+		return p.newError(ErrExpectedArgument, MsgExpectedArguments, option.Nargs, option, len(args))
+	}
+
+	if option.tag.Get("unquote") != "false" && !option.splitsValues() {
+		for i := range args {
+			arg, err := unquoteIfPossible(args[i])
+
+			if err != nil {
+				return err
+			}
+
+			args[i] = arg
+		}
+	}
+
+	return option.setNargs(args)
+}
+
 func (p *Parser) marshalError(option *Option, err error) *Error {
-	s := "invalid argument for flag `%s'"
+	// The conversion error may contain the value
+	if option.Secret {
+		return p.newError(ErrMarshal, MsgInvalidSecretArgument, option)
+	}
 
 	expected := p.expectedType(option)
 
 	if expected != "" {
-		s = s + " (expected " + expected + ")"
+		return p.newError(ErrMarshal, MsgInvalidArgumentExpected, option, expected, p.errorMessage(err))
 	}
 
-	return newErrorf(ErrMarshal, s+": %s",
-		option,
-		err.Error())
+	return p.newError(ErrMarshal, MsgInvalidArgument, option, p.errorMessage(err))
 }
 
 func (p *Parser) expectedType(option *Option) string {
-	valueType := option.value.Type()
+	valueType := unwrapOptional(option.value).Type()
 
-	if valueType.Kind() == reflect.Func {
+	if tp := specialType(valueType); tp != nil {
+		return p.specialTypeName(tp, option.tag)
+	}
+
+	// The arguments of options with multiple arguments are named in the
+	// error itself
+	if option.nargsStructType() != nil {
+		return ""
+	}
+
+	if structOptionType(valueType) != nil {
+		return p.Message(MsgTypeStruct)
+	}
+
+	// The type of options imported from a flag.FlagSet is not meaningful
+	// to the user
+	if valueType.Kind() == reflect.Func || valueType == reflect.TypeOf(flagValue{}) {
 		return ""
 	}
 
@@ -596,7 +771,7 @@ func (p *Parser) expectedType(option *Option) string {
 }
 
 func (p *Parser) parseLong(s *parseState, name string, argument *string) error {
-	if option := s.lookup.longNames[name]; option != nil {
+	if option := s.lookup.longOption(name); option != nil {
 		// Only long options that are required can consume an argument
 		// from the argument list
 		canarg := !option.OptionalArgument
@@ -604,7 +779,75 @@ func (p *Parser) parseLong(s *parseState, name string, argument *string) error {
 		return p.parseOption(s, name, option, canarg, argument)
 	}
 
-	return newErrorf(ErrUnknownFlag, "unknown flag `%s'", name)
+	return p.newError(ErrUnknownFlag, MsgUnknownFlag, name)
+}
+
+// lookupPrefixed returns the option specified by name using the given
+// additional option prefix, and whether the option is negated.
+func (p *parseState) lookupPrefixed(prefix OptionPrefix, name string, islong bool) (*Option, bool) {
+	prefixedNames, names := p.lookup.prefixedShortNames, p.lookup.shortNames
+
+	if islong {
+		prefixedNames, names = p.lookup.prefixedLongNames, p.lookup.longNames
+		name = p.lookup.parser.longNameKey(name)
+	}
+
+	if option := prefixedNames[string(prefix.Char)+name]; option != nil {
+		return option, false
+	}
+
+	option := names[name]
+
+	if option == nil || !prefix.Negate {
+		return option, false
+	}
+
+	if !option.isBool() || option.isFunc() {
+		return nil, false
+	}
+
+	return option, true
+}
+
+func (p *Parser) parsePrefixed(s *parseState, prefix OptionPrefix, name string, islong bool, argument *string) error {
+	option, negate := s.lookupPrefixed(prefix, name, islong)
+
+	if option == nil {
+		return p.newError(ErrUnknownFlag, MsgUnknownFlag, string(prefix.Char)+name)
+	}
+
+	if !negate {
+		return p.parseOption(s, name, option, !option.OptionalArgument, argument)
+	}
+
+	if argument != nil {
+		return p.newError(ErrNoArgumentForBool, MsgBoolArgument, option)
+	}
+
+	value := "false"
+
+	if err := option.Set(&value); err != nil {
+		if _, ok := err.(*Error); !ok {
+			err = p.marshalError(option, err)
+		}
+
+		return err
+	}
+
+	option.setSource(SourceCommandLine)
+	return nil
+}
+
+// isSingleDashLong returns whether optname, specified with the given prefix,
+// refers to a long option specified using a single dash (see
+// SingleDashLong).
+func (p *Parser) isSingleDashLong(s *parseState, prefix string, optname string) bool {
+	if (p.Options&SingleDashLong) == None || prefix != string(posixShortOptDelimiter) {
+		return false
+	}
+
+	name, _, _ := p.splitOption(prefix, optname, true)
+	return s.lookup.longOption(name) != nil
 }
 
 func (p *Parser) splitShortConcatArg(s *parseState, optname string) (string, *string) {
@@ -629,6 +872,12 @@ func (p *Parser) parseShort(s *parseState, optname string, argument *string) err
 		optname, argument = p.splitShortConcatArg(s, optname)
 	}
 
+	// Short options cannot be clustered when long options may be
+	// specified using a single dash
+	if (p.Options&SingleDashLong) != None && utf8.RuneCountInString(optname) > 1 {
+		return p.newError(ErrUnknownFlag, MsgUnknownFlag, optname)
+	}
+
 	for i, c := range optname {
 		shortname := string(c)
 
@@ -641,7 +890,7 @@ func (p *Parser) parseShort(s *parseState, optname string, argument *string) err
 				return err
 			}
 		} else {
-			return newErrorf(ErrUnknownFlag, "unknown flag `%s'", shortname)
+			return p.newError(ErrUnknownFlag, MsgUnknownFlag, shortname)
 		}
 
 		// Only the first option can have a concatted argument, so just
@@ -655,16 +904,18 @@ func (p *Parser) parseShort(s *parseState, optname string, argument *string) err
 func (p *parseState) addArgs(args ...string) error {
 	for len(p.positional) > 0 && len(args) > 0 {
 		arg := p.positional[0]
+		value := arg.value
 
-		if err := convert(args[0], arg.value, arg.tag); err != nil {
-			p.err = err
-			return err
+		if arg.isArray() {
+			value = value.Index(p.positionalIndex)
 		}
 
-		if !arg.isRemaining() {
-			p.positional = p.positional[1:]
+		if err := convert(p.lookup.parser, args[0], value, arg.tag); err != nil {
+			p.err = err
+			return err
 		}
 
+		p.advancePositional()
 		args = args[1:]
 	}
 
@@ -672,6 +923,28 @@ func (p *parseState) addArgs(args ...string) error {
 	return nil
 }
 
+// advancePositional records that a value was assigned to the first
+// positional argument, and moves on to the next positional argument once
+// the first one does not accept any more values.
+func (p *parseState) advancePositional() {
+	arg := p.positional[0]
+
+	if arg.isRemaining() {
+		return
+	}
+
+	if arg.isArray() {
+		p.positionalIndex++
+
+		if p.positionalIndex < arg.value.Len() {
+			return
+		}
+	}
+
+	p.positional = p.positional[1:]
+	p.positionalIndex = 0
+}
+
 func (p *Parser) parseNonOption(s *parseState) error {
 	if len(s.positional) > 0 {
 		return s.addArgs(s.arg)
@@ -685,7 +958,7 @@ func (p *Parser) parseNonOption(s *parseState) error {
 			return nil
 		} else if !s.command.SubcommandsOptional {
 			s.addArgs(s.arg)
-			return newErrorf(ErrUnknownCommand, "Unknown command `%s'", s.arg)
+			return p.newError(ErrUnknownCommand, MsgUnknownCommand, s.arg)
 		}
 	}
 
@@ -701,14 +974,34 @@ func (p *Parser) showBuiltinHelp() error {
 
 func (p *Parser) printError(err error) error {
 	if err != nil && (p.Options&PrintErrors) != None {
-		flagsErr, ok := err.(*Error)
-
-		if ok && flagsErr.Type == ErrHelp {
-			fmt.Fprintln(os.Stdout, err)
-		} else {
-			fmt.Fprintln(os.Stderr, err)
-		}
+		p.writeError(err)
 	}
 
 	return err
 }
+
+func (p *Parser) writeError(err error) {
+	flagsErr, ok := err.(*Error)
+
+	if ok && flagsErr.Type == ErrHelp {
+		fmt.Fprintln(p.stdout(), err)
+	} else {
+		fmt.Fprintln(p.stderr(), err)
+	}
+}
+
+func (p *Parser) stdout() io.Writer {
+	if p.Stdout != nil {
+		return p.Stdout
+	}
+
+	return os.Stdout
+}
+
+func (p *Parser) stderr() io.Writer {
+	if p.Stderr != nil {
+		return p.Stderr
+	}
+
+	return os.Stderr
+}
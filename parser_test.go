@@ -1,6 +1,7 @@
 package flags
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
@@ -680,3 +681,97 @@ func TestCommandHandler(t *testing.T) {
 
 	assertStringArray(t, executedArgs, []string{"arg1", "arg2"})
 }
+
+func TestPrintErrorsWriters(t *testing.T) {
+	var opts struct {
+		Value bool `short:"v" description:"A value"`
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	p := NewNamedParser("test", Default)
+	p.AddGroup("Application Options", "", &opts)
+	p.Stdout = &stdout
+	p.Stderr = &stderr
+
+	if _, err := p.ParseArgs([]string{"-x"}); err == nil {
+		t.Fatal("Expected error for unknown flag")
+	}
+
+	assertString(t, stderr.String(), "unknown flag `x'\n")
+	assertString(t, stdout.String(), "")
+
+	stderr.Reset()
+
+	if _, err := p.ParseArgs([]string{"-h"}); !WroteHelp(err) {
+		t.Fatalf("Expected help error, but got %v", err)
+	}
+
+	if !strings.HasPrefix(stdout.String(), "Usage:\n  test [OPTIONS]") {
+		t.Errorf("Expected help message on stdout, but got %q", stdout.String())
+	}
+
+	assertString(t, stderr.String(), "")
+}
+
+func TestSingleDashLong(t *testing.T) {
+	var opts struct {
+		Verbose bool   `short:"v" long:"verbose"`
+		Port    int    `short:"p" long:"port"`
+		All     bool   `short:"a"`
+		Name    string `long:"name"`
+	}
+
+	p := NewParser(&opts, SingleDashLong)
+	p.OptionStyle = OptionStylePOSIX
+
+	ret, err := p.ParseArgs([]string{"-verbose", "-port=80", "-name", "x", "--name=y", "-a", "rest"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !opts.Verbose || !opts.All {
+		t.Errorf("Expected Verbose and All to be true")
+	}
+
+	if opts.Port != 80 {
+		t.Errorf("Expected Port to be 80, but got %v", opts.Port)
+	}
+
+	assertString(t, opts.Name, "y")
+	assertStringArray(t, ret, []string{"rest"})
+
+	_, err = p.ParseArgs([]string{"-p8080"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if opts.Port != 8080 {
+		t.Errorf("Expected Port to be 8080, but got %v", opts.Port)
+	}
+
+	_, err = p.ParseArgs([]string{"-va"})
+	assertError(t, err, ErrUnknownFlag, "unknown flag `va'")
+}
+
+func TestSingleDashLongHelp(t *testing.T) {
+	var opts struct {
+		Verbose bool `short:"v" long:"verbose" description:"Verbose output"`
+		Debug   bool `long:"debug" description:"Debug output"`
+	}
+
+	p := NewNamedParser("test", SingleDashLong)
+	p.OptionStyle = OptionStylePOSIX
+	p.AddGroup("Application Options", "", &opts)
+
+	var b bytes.Buffer
+	p.WriteHelp(&b)
+
+	for _, expected := range []string{"-v, -verbose", "    -debug"} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("Expected help to contain %q, but got:\n%s", expected, b.String())
+		}
+	}
+}
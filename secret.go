@@ -0,0 +1,87 @@
+package flags
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+// secretMask replaces the values of secret options in the help message,
+// errors and ini files.
+const secretMask = "******"
+
+// maskSecret returns value, or the secret mask if the option is secret.
+func (option *Option) maskSecret(value string) string {
+	if option.Secret {
+		return secretMask
+	}
+
+	return value
+}
+
+// readFile reads the value of the option from the given file, without the
+// trailing newline.
+func (option *Option) readFile(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+
+	if err != nil {
+		ret := option.parser().newError(ErrMarshal, MsgReadFile, option, err)
+		ret.Err = err
+
+		return "", ret
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+}
+
+// fileOption returns an option with the given long name which sets the value
+// of the option from the file given as its argument (see the file tag).
+func (option *Option) fileOption(name string) *Option {
+	set := func(filename string) error {
+		value, err := option.readFile(filename)
+
+		if err != nil {
+			return err
+		}
+
+		if err := option.Set(&value); err != nil {
+			if _, ok := err.(*Error); !ok {
+				err = option.parser().marshalError(option, err)
+			}
+
+			return err
+		}
+
+		option.setSource(SourceCommandLine)
+		return nil
+	}
+
+	return &Option{
+		LongName:  name,
+		ValueName: "FILE",
+		Hidden:    option.Hidden,
+		Prefix:    option.Prefix,
+
+		group:   option.group,
+		fileFor: option,
+
+		field: reflect.StructField{
+			Name: option.field.Name + "File",
+			Type: reflect.TypeOf(set),
+		},
+		value: reflect.ValueOf(set),
+		tag:   newMultiTag(`no-ini:"true" no-env:"true"`),
+	}
+}
+
+// description returns the description of the option shown in the help, man
+// page and completions. The description of an option reading the value of
+// another option from a file is derived from the description of that option
+// using the parser's message catalog.
+func (option *Option) description() string {
+	if option.fileFor != nil && option.fileFor.Description != "" {
+		return option.parser().Message(MsgFromFile, option.fileFor.Description)
+	}
+
+	return option.Description
+}
@@ -0,0 +1,186 @@
+package flags
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type secretOptions struct {
+	Password string `long:"password" file:"password-file" env:"TEST_SECRET_PW" env-file:"TEST_SECRET_PW_FILE" description:"Database password"`
+	Pin      int    `long:"pin" secret:"true" default:"1234" description:"PIN code"`
+	User     string `long:"user" description:"Database user"`
+}
+
+func writeSecretFile(t *testing.T, contents string) string {
+	filename := filepath.Join(t.TempDir(), "secret")
+
+	if err := os.WriteFile(filename, []byte(contents), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return filename
+}
+
+func TestSecretFile(t *testing.T) {
+	filename := writeSecretFile(t, "s3cret\n")
+
+	var opts secretOptions
+
+	p := NewParser(&opts, None)
+
+	if _, err := p.ParseArgs([]string{"--password-file", filename}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.Password, "s3cret")
+
+	option := p.FindOptionByLongName("password")
+
+	if !option.Secret {
+		t.Errorf("Expected option to be secret")
+	}
+
+	if option.Source() != SourceCommandLine {
+		t.Errorf("Expected source %v, but got %v", SourceCommandLine, option.Source())
+	}
+
+	if p.FindOptionByLongName("user").Secret {
+		t.Errorf("Expected option not to be secret")
+	}
+}
+
+func TestSecretEnvFile(t *testing.T) {
+	oldEnv := EnvSnapshot()
+	defer oldEnv.Restore()
+
+	os.Setenv("TEST_SECRET_PW_FILE", writeSecretFile(t, "from-file\r\n"))
+
+	var opts secretOptions
+	assertParseSuccess(t, &opts)
+
+	assertString(t, opts.Password, "from-file")
+
+	// The env key takes precedence over the env file key
+	os.Setenv("TEST_SECRET_PW", "from-env")
+
+	opts = secretOptions{}
+	assertParseSuccess(t, &opts)
+
+	assertString(t, opts.Password, "from-env")
+}
+
+func TestSecretFileError(t *testing.T) {
+	var opts secretOptions
+
+	p := NewParser(&opts, None)
+	_, err := p.ParseArgs([]string{"--password-file", filepath.Join(t.TempDir(), "missing")})
+
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected a not exist error, but got %v", err)
+	}
+
+	if !strings.HasPrefix(err.Error(), fmt.Sprintf("could not read the value of flag `%spassword': ", defaultLongOptDelimiter)) {
+		t.Errorf("Unexpected error message: %s", err.Error())
+	}
+}
+
+func TestSecretMaskedInErrors(t *testing.T) {
+	var opts secretOptions
+
+	assertParseFail(t, ErrMarshal, fmt.Sprintf("invalid argument for flag `%spin'", defaultLongOptDelimiter), &opts, "--pin", "hunter2")
+
+	var choiceOpts struct {
+		Token string `long:"token" secret:"true" choice:"a" choice:"b"`
+	}
+
+	assertParseFail(t, ErrInvalidChoice, fmt.Sprintf("Invalid value `******' for option `%stoken'. Allowed values are: a or b", defaultLongOptDelimiter), &choiceOpts, "--token", "hunter2")
+
+	filename := writeSecretFile(t, "not-a-number\n")
+
+	var fileOpts struct {
+		Pin int `long:"pin" file:"pin-file"`
+	}
+
+	assertParseFail(t, ErrMarshal, fmt.Sprintf("invalid argument for flag `%spin'", defaultLongOptDelimiter), &fileOpts, "--pin-file", filename)
+}
+
+func TestSecretMaskedInHelp(t *testing.T) {
+	var opts secretOptions
+
+	p := NewNamedParser("TestSecret", HelpFlag)
+	p.AddGroup("Application Options", "", &opts)
+	p.OptionStyle = OptionStylePOSIX
+
+	_, err := p.ParseArgs([]string{"--help"})
+	assertError(t, err, ErrHelp, err.Error())
+
+	help := err.Error()
+
+	for _, s := range []string{"--password-file=FILE", "Database password (read from a file)", "(default: ******)"} {
+		if !strings.Contains(help, s) {
+			t.Errorf("Expected help to contain %q, but got:\n%s", s, help)
+		}
+	}
+
+	if strings.Contains(help, "1234") {
+		t.Errorf("Expected help not to contain the secret default, but got:\n%s", help)
+	}
+
+	var out bytes.Buffer
+	p.WriteManPage(&out)
+
+	if strings.Contains(out.String(), "1234") {
+		t.Errorf("Expected man page not to contain the secret default, but got:\n%s", out.String())
+	}
+}
+
+func TestSecretFileEnv(t *testing.T) {
+	var opts secretOptions
+
+	p := NewNamedParser("TestSecret", None)
+	p.AddGroup("Application Options", "", &opts)
+	p.EnvPrefix = "APP"
+
+	// The file option is not bound to the environment, env-file is used
+	// instead
+	assertString(t, p.FindOptionByLongName("password-file").EnvKeyWithNamespace(), "")
+
+	var help bytes.Buffer
+	p.WriteHelp(&help)
+
+	if strings.Contains(help.String(), "APP_PASSWORD_FILE") {
+		t.Errorf("Expected file option without env key, but got:\n%s", help.String())
+	}
+}
+
+func TestSecretMaskedInIni(t *testing.T) {
+	var opts secretOptions
+
+	p := NewParser(&opts, None)
+
+	if _, err := p.ParseArgs([]string{"--password", "s3cret", "--user", "me"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	NewIniParser(p).Write(&out, IniNone)
+
+	expected := `[Application Options]
+; Password = ******
+User = me
+
+`
+
+	assertDiff(t, out.String(), expected, "ini")
+
+	err := NewIniParser(p).Parse(strings.NewReader("[Application Options]\nPin = hunter2\n"))
+
+	if err == nil || strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("Expected an error without the secret value, but got %v", err)
+	}
+}
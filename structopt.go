@@ -0,0 +1,220 @@
+package flags
+
+import (
+	"encoding"
+	"flag"
+	"reflect"
+	"strings"
+)
+
+// structKey describes a field of a struct valued option, which is specified
+// as key=value on the command line.
+type structKey struct {
+	name     string
+	index    int
+	tag      multiTag
+	defaults []string
+	choices  []string
+	required bool
+}
+
+var unmarshalerTypes = []reflect.Type{
+	reflect.TypeOf((*Unmarshaler)(nil)).Elem(),
+	reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem(),
+	reflect.TypeOf((*flag.Value)(nil)).Elem(),
+}
+
+// structOptionType returns the struct type of a struct valued option of
+// type tp, dereferencing slices and pointers, or nil if tp is not a struct
+// valued option type. Structs which unmarshal themselves, and the built-in
+// special types, are not struct valued options.
+func structOptionType(tp reflect.Type) reflect.Type {
+	for tp.Kind() == reflect.Slice || tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+
+	if tp.Kind() != reflect.Struct || isSpecialType(tp) || optionalElemType(tp) != nil {
+		return nil
+	}
+
+	for _, u := range unmarshalerTypes {
+		if tp.Implements(u) || reflect.PtrTo(tp).Implements(u) {
+			return nil
+		}
+	}
+
+	return tp
+}
+
+// structKeys returns the keys of the exported fields of the struct type tp.
+// The key of a field is given by its key tag, or otherwise by its lower
+// case name.
+func structKeys(tp reflect.Type) ([]structKey, error) {
+	var keys []structKey
+
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		mtag := newMultiTag(string(field.Tag))
+
+		if err := mtag.Parse(); err != nil {
+			return nil, err
+		}
+
+		if mtag.Get("no-flag") != "" {
+			continue
+		}
+
+		name := mtag.Get("key")
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		keys = append(keys, structKey{
+			name:     name,
+			index:    i,
+			tag:      mtag,
+			defaults: mtag.GetMany("default"),
+			choices:  mtag.GetMany("choice"),
+			required: !isStringFalsy(mtag.Get("required")),
+		})
+	}
+
+	return keys, nil
+}
+
+func structKeyNames(keys []structKey) []string {
+	names := make([]string, len(keys))
+
+	for i, key := range keys {
+		names[i] = key.name
+	}
+
+	return names
+}
+
+// convertStruct sets the fields of the struct retval from the comma
+// separated key=value pairs in val. The key of a boolean field may be
+// specified without a value to set it to true. Fields which are not
+// specified are set to their default value. The values of keys with choices
+// are matched like the values of options with choices (see
+// IgnoreChoiceCase).
+func convertStruct(p *Parser, val string, retval reflect.Value) error {
+	keys, err := structKeys(retval.Type())
+
+	if err != nil {
+		return err
+	}
+
+	parts, err := splitDelimited(val, ",")
+
+	if err != nil {
+		return err
+	}
+
+	retval.Set(reflect.Zero(retval.Type()))
+	specified := make(map[string]bool)
+
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+
+		var key *structKey
+
+		for i := range keys {
+			if keys[i].name == kv[0] {
+				key = &keys[i]
+				break
+			}
+		}
+
+		if key == nil {
+			return newMessageError(MsgUnknownStructKey, kv[0], strings.Join(structKeyNames(keys), ", "))
+		}
+
+		field := retval.Field(key.index)
+		var value string
+
+		if len(kv) == 2 {
+			value = kv[1]
+		} else if field.Kind() != reflect.Bool {
+			return newMessageError(MsgExpectedStructValue, key.name)
+		}
+
+		if len(key.choices) != 0 {
+			choice, found := p.matchChoice(key.choices, value)
+
+			if !found {
+				return newMessageError(MsgInvalidStructChoice, value, key.name, strings.Join(key.choices, ", "))
+			}
+
+			value = choice
+		}
+
+		if err := convert(p, value, field, key.tag); err != nil {
+			return &messageError{
+				message: func(p *Parser) string {
+					return p.Message(MsgInvalidStructValue, value, key.name, p.errorMessage(err))
+				},
+			}
+		}
+
+		specified[key.name] = true
+	}
+
+	for _, key := range keys {
+		if specified[key.name] {
+			continue
+		}
+
+		if key.required {
+			return newMessageError(MsgMissingStructKey, key.name)
+		}
+
+		for _, def := range key.defaults {
+			if err := convert(p, def, retval.Field(key.index), key.tag); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// convertStructToString formats the non-zero fields of the struct val as
+// comma separated key=value pairs.
+func convertStructToString(val reflect.Value) (string, error) {
+	keys, err := structKeys(val.Type())
+
+	if err != nil {
+		return "", err
+	}
+
+	var parts []string
+
+	for _, key := range keys {
+		field := val.Field(key.index)
+
+		if field.IsZero() {
+			continue
+		}
+
+		s, err := convertToString(field, key.tag)
+
+		if err != nil {
+			return "", err
+		}
+
+		parts = append(parts, key.name+"="+s)
+	}
+
+	return joinDelimited(parts, ","), nil
+}
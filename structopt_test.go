@@ -0,0 +1,127 @@
+package flags
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type structOptDatabase struct {
+	Host    string `required:"true"`
+	Port    int    `default:"5432"`
+	SSLMode string `key:"sslmode" choice:"disable" choice:"require"`
+	Verbose bool
+	secret  string
+}
+
+func TestStructOption(t *testing.T) {
+	var opts = struct {
+		DB      structOptDatabase   `long:"db"`
+		Replica []structOptDatabase `long:"replica"`
+	}{}
+
+	assertParseSuccess(t, &opts,
+		"--db", "host=localhost,sslmode=require,verbose",
+		"--replica", "host=a,port=1",
+		"--replica", `host="b,c"`)
+
+	assertString(t, opts.DB.Host, "localhost")
+	assertString(t, opts.DB.SSLMode, "require")
+
+	if opts.DB.Port != 5432 {
+		t.Errorf("Expected Port to be 5432, but got %v", opts.DB.Port)
+	}
+
+	if !opts.DB.Verbose {
+		t.Errorf("Expected Verbose to be true")
+	}
+
+	if len(opts.Replica) != 2 {
+		t.Fatalf("Expected 2 replicas, but got %v", opts.Replica)
+	}
+
+	assertString(t, opts.Replica[0].Host, "a")
+	assertString(t, opts.Replica[1].Host, "b,c")
+
+	if opts.Replica[0].Port != 1 || opts.Replica[1].Port != 5432 {
+		t.Errorf("Expected ports 1 and 5432, but got %v and %v", opts.Replica[0].Port, opts.Replica[1].Port)
+	}
+}
+
+func TestStructOptionErrors(t *testing.T) {
+	var opts = struct {
+		DB structOptDatabase `long:"db"`
+	}{}
+
+	prefix := "invalid argument for flag `" + defaultLongOptDelimiter + "db' (expected comma separated key=value pairs): "
+
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"port=1", "missing required key `host'"},
+		{"host=a,user=b", "unknown key `user', expected one of: host, port, sslmode, verbose"},
+		{"host=a,sslmode=allow", "invalid value `allow' for key `sslmode', allowed values are: disable, require"},
+		{"host=a,port=x", "invalid value `x' for key `port': strconv.ParseInt: parsing \"x\": invalid syntax"},
+		{"host", "expected value for key `host'"},
+	}
+
+	for _, test := range tests {
+		assertParseFail(t, ErrMarshal, prefix+test.expected, &opts, "--db", test.value)
+	}
+}
+
+func TestStructOptionIgnoreChoiceCase(t *testing.T) {
+	var opts = struct {
+		DB structOptDatabase `long:"db"`
+	}{}
+
+	p := NewParser(&opts, IgnoreChoiceCase)
+
+	if _, err := p.ParseArgs([]string{"--db", "host=a,sslmode=REQUIRE"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.DB.SSLMode, "require")
+
+	assertParseFail(t, ErrMarshal, "invalid argument for flag `"+defaultLongOptDelimiter+"db' (expected comma separated key=value pairs): invalid value `REQUIRE' for key `sslmode', allowed values are: disable, require",
+		&opts, "--db", "host=a,sslmode=REQUIRE")
+}
+
+func TestStructOptionHelp(t *testing.T) {
+	var opts = struct {
+		DB structOptDatabase `long:"db" description:"Database" default:"host=localhost"`
+	}{}
+
+	p := NewNamedParser("test", None)
+	p.AddGroup("Application Options", "", &opts)
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var help bytes.Buffer
+	p.WriteHelp(&help)
+
+	for _, expected := range []string{"Database (keys: host, port, sslmode, verbose)", "host=localhost)"} {
+		if !strings.Contains(help.String(), expected) {
+			t.Errorf("Expected help to contain %q, but got:\n%s", expected, help.String())
+		}
+	}
+
+	opts.DB.Host = "a,b"
+
+	var ini bytes.Buffer
+	inip := NewIniParser(p)
+	inip.Write(&ini, IniNone)
+
+	assertString(t, ini.String(), "[Application Options]\nDB = host=a\\,b,port=5432\n\n")
+
+	opts.DB = structOptDatabase{}
+
+	if err := inip.Parse(&ini); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertString(t, opts.DB.Host, "a,b")
+}
@@ -0,0 +1,228 @@
+package flags
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+// Built-in special types which are converted directly, without requiring
+// user defined wrapper types.
+var (
+	ipType       = reflect.TypeOf(net.IP{})
+	ipNetType    = reflect.TypeOf(net.IPNet{})
+	addrPortType = reflect.TypeOf(netip.AddrPort{})
+	urlType      = reflect.TypeOf(url.URL{})
+	regexpType   = reflect.TypeOf(regexp.Regexp{})
+	timeType     = reflect.TypeOf(time.Time{})
+	locationType = reflect.TypeOf(time.Location{})
+)
+
+func isSpecialType(tp reflect.Type) bool {
+	switch tp {
+	case ipType, ipNetType, addrPortType, urlType, regexpType, timeType, locationType:
+		return true
+	}
+
+	return false
+}
+
+// specialType returns the special type of a value of type tp, dereferencing
+// slices, maps, pointers and optionals, or nil if it is not a special type.
+func specialType(tp reflect.Type) reflect.Type {
+	for {
+		if isSpecialType(tp) {
+			return tp
+		}
+
+		if elem := optionalElemType(tp); elem != nil {
+			tp = elem
+			continue
+		}
+
+		switch tp.Kind() {
+		case reflect.Slice, reflect.Map, reflect.Ptr:
+			tp = tp.Elem()
+		default:
+			return nil
+		}
+	}
+}
+
+func timeLayout(options multiTag) string {
+	if layout := options.Get("layout"); layout != "" {
+		return layout
+	}
+
+	return time.RFC3339
+}
+
+// convertSpecial converts val into retval if retval is a special type, or a
+// pointer to a special type. It returns whether retval is a special type.
+func convertSpecial(val string, retval reflect.Value, options multiTag) (bool, error) {
+	tp := retval.Type()
+
+	if tp.Kind() == reflect.Ptr && isSpecialType(tp.Elem()) {
+		if retval.IsNil() {
+			retval.Set(reflect.New(tp.Elem()))
+		}
+
+		return convertSpecial(val, retval.Elem(), options)
+	}
+
+	var parsed interface{}
+	var err error
+
+	switch tp {
+	case ipType:
+		if ip := net.ParseIP(val); ip != nil {
+			parsed = ip
+		} else {
+			err = newMessageError(MsgInvalidIP, val)
+		}
+	case ipNetType:
+		var ipnet *net.IPNet
+
+		if _, ipnet, err = net.ParseCIDR(val); err == nil {
+			parsed = *ipnet
+		}
+	case addrPortType:
+		parsed, err = netip.ParseAddrPort(val)
+	case urlType:
+		var u *url.URL
+
+		if u, err = url.Parse(val); err == nil {
+			parsed = *u
+		}
+	case regexpType:
+		var re *regexp.Regexp
+
+		if re, err = regexp.Compile(val); err == nil {
+			parsed = *re
+		}
+	case timeType:
+		parsed, err = time.Parse(timeLayout(options), val)
+	case locationType:
+		var loc *time.Location
+
+		if loc, err = time.LoadLocation(val); err == nil {
+			parsed = *loc
+		}
+	default:
+		return false, nil
+	}
+
+	if err != nil {
+		return true, err
+	}
+
+	retval.Set(reflect.ValueOf(parsed))
+	return true, nil
+}
+
+// convertSpecialToString converts val to a string if val is a special type,
+// or a pointer to a special type. It returns whether val is a special type.
+func convertSpecialToString(val reflect.Value, options multiTag) (bool, string) {
+	tp := val.Type()
+
+	if tp.Kind() == reflect.Ptr && isSpecialType(tp.Elem()) {
+		if val.IsNil() {
+			return true, ""
+		}
+
+		return convertSpecialToString(val.Elem(), options)
+	}
+
+	if !isSpecialType(tp) {
+		return false, ""
+	}
+
+	// Copy the value so that methods with pointer receivers can be called
+	// on non addressable values
+	ptr := reflect.New(tp)
+	ptr.Elem().Set(val)
+
+	switch v := ptr.Interface().(type) {
+	case *net.IP:
+		if len(*v) == 0 {
+			return true, ""
+		}
+
+		return true, v.String()
+	case *net.IPNet:
+		if v.IP == nil {
+			return true, ""
+		}
+
+		return true, v.String()
+	case *netip.AddrPort:
+		if !v.IsValid() {
+			return true, ""
+		}
+
+		return true, v.String()
+	case *url.URL:
+		return true, v.String()
+	case *regexp.Regexp:
+		return true, v.String()
+	case *time.Time:
+		if v.IsZero() {
+			return true, ""
+		}
+
+		return true, v.Format(timeLayout(options))
+	case *time.Location:
+		return true, v.String()
+	}
+
+	return false, ""
+}
+
+// specialValueName returns the default value name shown in the help for
+// options of the given special type.
+func specialValueName(tp reflect.Type) string {
+	switch tp {
+	case ipType:
+		return "IP"
+	case ipNetType:
+		return "CIDR"
+	case addrPortType:
+		return "ADDR:PORT"
+	case urlType:
+		return "URL"
+	case regexpType:
+		return "REGEXP"
+	case timeType:
+		return "TIME"
+	case locationType:
+		return "ZONE"
+	}
+
+	return ""
+}
+
+// specialTypeName returns the human readable name of the given special
+// type, used in error messages.
+func (p *Parser) specialTypeName(tp reflect.Type, options multiTag) string {
+	switch tp {
+	case ipType:
+		return p.Message(MsgTypeIP)
+	case ipNetType:
+		return p.Message(MsgTypeIPNet)
+	case addrPortType:
+		return p.Message(MsgTypeAddrPort)
+	case urlType:
+		return p.Message(MsgTypeURL)
+	case regexpType:
+		return p.Message(MsgTypeRegexp)
+	case timeType:
+		return p.Message(MsgTypeTime, timeLayout(options))
+	case locationType:
+		return p.Message(MsgTypeLocation)
+	}
+
+	return ""
+}
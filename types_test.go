@@ -0,0 +1,145 @@
+package flags
+
+import (
+	"bytes"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpecialTypes(t *testing.T) {
+	var opts = struct {
+		IP       net.IP         `long:"ip"`
+		IPs      []net.IP       `long:"ips"`
+		Network  net.IPNet      `long:"network"`
+		AddrPort netip.AddrPort `long:"addr"`
+		URL      *url.URL       `long:"url"`
+		Regexp   *regexp.Regexp `long:"regexp"`
+		Time     time.Time      `long:"time"`
+		Date     time.Time      `long:"date" layout:"2006-01-02"`
+		Location *time.Location `long:"location"`
+	}{}
+
+	ret := assertParseSuccess(t, &opts,
+		"--ip", "10.0.0.1",
+		"--ips", "::1", "--ips", "192.168.0.1",
+		"--network", "192.168.1.5/24",
+		"--addr", "127.0.0.1:8080",
+		"--url", "https://example.com/path?q=1",
+		"--regexp", "^a+b$",
+		"--time", "2021-03-04T05:06:07Z",
+		"--date", "2021-03-04",
+		"--location", "UTC")
+
+	assertStringArray(t, ret, []string{})
+
+	assertString(t, opts.IP.String(), "10.0.0.1")
+
+	if len(opts.IPs) != 2 {
+		t.Fatalf("Expected 2 IPs, but got %v", opts.IPs)
+	}
+
+	assertString(t, opts.IPs[0].String(), "::1")
+	assertString(t, opts.IPs[1].String(), "192.168.0.1")
+	assertString(t, opts.Network.String(), "192.168.1.0/24")
+	assertString(t, opts.AddrPort.String(), "127.0.0.1:8080")
+	assertString(t, opts.URL.Host, "example.com")
+	assertString(t, opts.URL.Query().Get("q"), "1")
+
+	if !opts.Regexp.MatchString("aab") || opts.Regexp.MatchString("abc") {
+		t.Errorf("Expected Regexp to match `aab' but not `abc'")
+	}
+
+	if !opts.Time.Equal(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)) {
+		t.Errorf("Expected Time to be 2021-03-04T05:06:07Z, but got %v", opts.Time)
+	}
+
+	if !opts.Date.Equal(time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected Date to be 2021-03-04, but got %v", opts.Date)
+	}
+
+	assertString(t, opts.Location.String(), "UTC")
+}
+
+func TestSpecialTypesErrors(t *testing.T) {
+	var opts = struct {
+		IP       net.IP         `long:"ip"`
+		Network  net.IPNet      `long:"network"`
+		AddrPort netip.AddrPort `long:"addr"`
+		Regexp   *regexp.Regexp `long:"regexp"`
+		Date     time.Time      `long:"date" layout:"2006-01-02"`
+	}{}
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--ip", "abc"}, "invalid argument for flag `" + defaultLongOptDelimiter + "ip' (expected IP address): invalid IP address: abc"},
+		{[]string{"--network", "10.0.0.1"}, "invalid argument for flag `" + defaultLongOptDelimiter + "network' (expected CIDR network): invalid CIDR address: 10.0.0.1"},
+		{[]string{"--addr", "10.0.0.1"}, "invalid argument for flag `" + defaultLongOptDelimiter + "addr' (expected IP address and port): "},
+		{[]string{"--regexp", "a("}, "invalid argument for flag `" + defaultLongOptDelimiter + "regexp' (expected regular expression): "},
+		{[]string{"--date", "04.03.2021"}, "invalid argument for flag `" + defaultLongOptDelimiter + "date' (expected time in the format 2006-01-02): "},
+	}
+
+	for _, test := range tests {
+		_, err := NewParser(&opts, None).ParseArgs(test.args)
+
+		if err == nil {
+			t.Errorf("Expected error for %v", test.args)
+			continue
+		}
+
+		if !strings.HasPrefix(err.Error(), test.expected) {
+			t.Errorf("Expected error %q to start with %q", err.Error(), test.expected)
+		}
+	}
+}
+
+func TestSpecialTypesHelp(t *testing.T) {
+	u, _ := url.Parse("http://localhost:8080")
+
+	var opts = struct {
+		IP   net.IP    `long:"ip" description:"ip"`
+		URL  *url.URL  `long:"url" description:"url"`
+		Date time.Time `long:"date" layout:"2006-01-02" description:"date"`
+	}{
+		IP:   net.IPv4(127, 0, 0, 1),
+		URL:  u,
+		Date: time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+	}
+
+	p := NewNamedParser("test", None)
+	p.AddGroup("Application Options", "", &opts)
+
+	if _, err := p.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var help bytes.Buffer
+	p.WriteHelp(&help)
+
+	for _, expected := range []string{
+		defaultLongOptDelimiter + "ip" + string(defaultNameArgDelimiter) + "IP",
+		"ip (default: 127.0.0.1)",
+		defaultLongOptDelimiter + "url" + string(defaultNameArgDelimiter) + "URL",
+		"url (default: http://localhost:8080)",
+		"date (default: 2021-03-04)",
+	} {
+		if !strings.Contains(help.String(), expected) {
+			t.Errorf("Expected help to contain %q, but got:\n%s", expected, help.String())
+		}
+	}
+
+	var ini bytes.Buffer
+	NewIniParser(p).Write(&ini, IniIncludeDefaults)
+
+	for _, expected := range []string{"IP = 127.0.0.1\n", "URL = http://localhost:8080\n", "Date = 2021-03-04\n"} {
+		if !strings.Contains(ini.String(), expected) {
+			t.Errorf("Expected ini to contain %q, but got:\n%s", expected, ini.String())
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package flags
+
+// Validator is the interface implemented by group and command data which
+// validate their values as a whole, e.g. to check rules involving several
+// options. Validate is called after parsing, once defaults have been applied
+// and required options have been checked, on the data of every group of the
+// active commands. It is also called after parsing an ini file (unless the
+// ini file is parsed as defaults), before the command line is parsed, see
+// IniParser.Parse. Errors returned by Validate are wrapped in an Error of
+// type ErrValidation, mentioning the group or command.
+type Validator interface {
+	// Validate returns an error if the values are invalid.
+	Validate() error
+}
+
+// validate calls Validate on the data of all the groups of the active
+// commands, returning the first error.
+func (p *Parser) validate() error {
+	var err error
+
+	p.eachActiveGroup(func(c *Command, g *Group) {
+		if err == nil {
+			err = p.validateGroup(c, g)
+		}
+	})
+
+	return err
+}
+
+func (p *Parser) validateGroup(c *Command, g *Group) error {
+	validator, ok := g.data.(Validator)
+
+	if !ok {
+		return nil
+	}
+
+	err := validator.Validate()
+
+	if err == nil {
+		return nil
+	}
+
+	if ferr, ok := err.(*Error); ok {
+		return ferr
+	}
+
+	var ret *Error
+
+	if g == c.Group {
+		ret = p.newError(ErrValidation, MsgInvalidCommandOptions, c.Name, err)
+	} else {
+		ret = p.newError(ErrValidation, MsgInvalidGroupOptions, p.groupDescription(g), err)
+	}
+
+	ret.Err = err
+	return ret
+}
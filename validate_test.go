@@ -0,0 +1,207 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var errStartAfterEnd = errors.New("start must be before end")
+
+type validateRange struct {
+	Start int `long:"start" default:"1"`
+	End   int `long:"end" default:"10"`
+
+	validated int
+}
+
+func (r *validateRange) Validate() error {
+	r.validated++
+
+	if r.Start >= r.End {
+		return errStartAfterEnd
+	}
+
+	return nil
+}
+
+type validateLogin struct {
+	Token    string `long:"token"`
+	User     string `long:"user"`
+	Password string `long:"password"`
+}
+
+func (l *validateLogin) Validate() error {
+	if l.Token == "" && (l.User == "" || l.Password == "") {
+		return errors.New("either token or user and password are required")
+	}
+
+	return nil
+}
+
+type validateCommand struct {
+	Login validateLogin `group:"Login Options"`
+
+	Force bool `long:"force" required:"true"`
+	Count int  `long:"count"`
+}
+
+func (c *validateCommand) Validate() error {
+	if c.Count < 0 {
+		return errors.New("count must not be negative")
+	}
+
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	var opts validateRange
+
+	assertParseSuccess(t, &opts, "--start", "5")
+
+	if opts.validated != 1 {
+		t.Errorf("Expected Validate to be called once, but got %d", opts.validated)
+	}
+
+	opts = validateRange{}
+	assertParseFail(t, ErrValidation, "invalid options in group `Application Options': start must be before end", &opts, "--start", "20")
+}
+
+func TestValidateUnwrap(t *testing.T) {
+	var opts validateRange
+
+	p := NewParser(&opts, None)
+	_, err := p.ParseArgs([]string{"--end", "0"})
+
+	if !errors.Is(err, errStartAfterEnd) {
+		t.Fatalf("Expected error to wrap %v, but got %v", errStartAfterEnd, err)
+	}
+}
+
+func TestValidateCommand(t *testing.T) {
+	var opts struct {
+		Verbose bool `short:"v"`
+
+		Run validateCommand `command:"run"`
+		Get validateCommand `command:"get"`
+	}
+
+	p := NewParser(&opts, None)
+	_, err := p.ParseArgs([]string{"run", "--force", "--count", "-1", "--token", "x"})
+
+	assertError(t, err, ErrValidation, "invalid options for command `run': count must not be negative")
+
+	// Groups of a command are validated as well
+	opts.Run = validateCommand{}
+	_, err = p.ParseArgs([]string{"run", "--force", "--user", "me"})
+
+	assertError(t, err, ErrValidation, "invalid options in group `Login Options': either token or user and password are required")
+
+	// Commands which are not active are not validated
+	_, err = p.ParseArgs([]string{"get", "--force", "--token", "x"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestValidateAfterRequired(t *testing.T) {
+	var opts struct {
+		Run validateCommand `command:"run"`
+	}
+
+	// Missing required options are reported before validation errors
+	assertParseFail(t, ErrRequired, fmt.Sprintf("the required flag `%sforce' was not specified", defaultLongOptDelimiter), &opts, "run", "--count", "-1")
+}
+
+type validateFlagsError struct {
+	Name string `long:"name"`
+}
+
+func (v *validateFlagsError) Validate() error {
+	if v.Name == "" {
+		return &Error{Type: ErrRequired, Message: "a name is required"}
+	}
+
+	return nil
+}
+
+func TestValidateFlagsError(t *testing.T) {
+	var opts validateFlagsError
+
+	assertParseFail(t, ErrRequired, "a name is required", &opts)
+}
+
+func TestValidateIni(t *testing.T) {
+	var opts validateRange
+
+	p := NewParser(&opts, None)
+	inip := NewIniParser(p)
+
+	err := inip.Parse(strings.NewReader("[Application Options]\nstart = 3\nend = 2\n"))
+	assertError(t, err, ErrValidation, "invalid options in group `Application Options': start must be before end")
+
+	// Values parsed as defaults are validated when parsing the command line
+	opts = validateRange{}
+	p = NewParser(&opts, None)
+	inip = NewIniParser(p)
+	inip.ParseAsDefaults = true
+
+	if err := inip.Parse(strings.NewReader("[Application Options]\nstart = 3\nend = 2\n")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err = p.ParseArgs([]string{"--end", "4"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if opts.Start != 3 || opts.End != 4 {
+		t.Errorf("Expected start 3 and end 4, but got %d and %d", opts.Start, opts.End)
+	}
+}
+
+func TestValidateIniThenArgs(t *testing.T) {
+	var opts validateRange
+
+	// The ini file is validated on its own, before the command line is
+	// parsed
+	p := NewParser(&opts, None)
+	err := NewIniParser(p).Parse(strings.NewReader("[Application Options]\nstart = 20\n"))
+
+	assertError(t, err, ErrValidation, "invalid options in group `Application Options': start must be before end")
+
+	// Parsed as defaults, the command line completes the values before
+	// they are validated
+	opts = validateRange{}
+	p = NewParser(&opts, None)
+
+	inip := NewIniParser(p)
+	inip.ParseAsDefaults = true
+
+	if err := inip.Parse(strings.NewReader("[Application Options]\nstart = 20\n")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := p.ParseArgs([]string{"--end=30"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if opts.Start != 20 || opts.End != 30 {
+		t.Errorf("Expected start 20 and end 30, but got %d and %d", opts.Start, opts.End)
+	}
+}
+
+func TestValidateGroupMessages(t *testing.T) {
+	var opts validateRange
+
+	p := NewParser(&opts, None)
+	p.Messages = MessageMap{
+		MsgApplicationOptions: "Anwendungsoptionen",
+	}
+
+	_, err := p.ParseArgs([]string{"--start=20"})
+	assertError(t, err, ErrValidation, "invalid options in group `Anwendungsoptionen': start must be before end")
+}